@@ -17,6 +17,12 @@ type ValidationRule struct {
 	Name string
 	// Validator argument
 	Args []string
+	// Negation of the rule
+	Not bool
+	// List of alternatives. Rule is valid when at least one alternative is valid
+	Alternatives []ValidationRules
+	// Alias name if rule is expanded alias
+	Alias string
 }
 
 // Basic validation rules
@@ -26,6 +32,8 @@ var basicValidationRules = map[string]ValidationCallback{
 	"required": IsRequiredValid,
 	// Enum validator
 	"enum": IsEnumValid,
+	// Case-insensitive enum validator
+	"ienum": IsEnumFoldValid,
 	// Range validation
 	"range": IsRangeValid,
 	// Regular expression validation
@@ -34,12 +42,64 @@ var basicValidationRules = map[string]ValidationCallback{
 	"min": IsMinValid,
 	// Check if value or length >= max
 	"max": IsMaxValid,
+	// Check if value > argument
+	"gt": IsGtValid,
+	// Check if value < argument
+	"lt": IsLtValid,
+	// Check if value >= argument
+	"gte": IsMinValid,
+	// Check if value <= argument
+	"lte": IsMaxValid,
 	// Check for digits. can specify len
 	"digit": IsDigits,
 	// Check if nil
 	"notnull": IsNotNullValid,
+	// Check if length of collection >= min
+	"minItems": IsMinItemsValid,
+	// Check if length of collection <= max
+	"maxItems": IsMaxItemsValid,
+	// Check length of collection or string
+	"len": IsLenValid,
+	// Check if collection items are unique
+	"unique": IsUniqueValid,
+	// Check if collection or string is not empty
+	"nonempty": IsNonEmptyValid,
+	// Check if time is before argument
+	"before": IsBeforeValid,
+	// Check if time is after argument
+	"after": IsAfterValid,
+	// Check if time is between arguments
+	"between": IsBetweenValid,
+	// Check if time is in the past
+	"past": IsPastValid,
+	// Check if time is in the future
+	"future": IsFutureValid,
+	// Check if time is not older than duration
+	"maxAge": IsMaxAgeValid,
+	// Check if number is multiple of argument
+	"multipleOf": IsMultipleOfValid,
+	// Check maximum count of decimal places
+	"scale": IsScaleValid,
+	// Check maximum count of digits and decimal places
+	"precision": IsPrecisionValid,
+	// Check if number > 0
+	"positive": IsPositiveValid,
+	// Check if number < 0
+	"negative": IsNegativeValid,
+	// Check if number != 0
+	"nonzero": IsNonZeroValid,
+	// Check if number is not NaN or Inf
+	"finite": IsFiniteValid,
 }
 
+// Dive marker. Rules after the marker are applied to collection elements or map values
+// Rules after "dive~keys" are applied to map keys
+const (
+	ruleDive   = "dive"
+	diveKeys   = "keys"
+	diveValues = "values"
+)
+
 // Will be used in validation method
 var actualValidationRules map[string]ValidationCallback
 
@@ -54,152 +114,123 @@ func PrepareActualValidationRules(customValidationRules map[string]ValidationCal
 	for s, callback := range customValidationRules {
 		actualValidationRules[s] = callback
 	}
+	resetPlans()
 }
 
 // ValidateStruct struct fields validation
 func ValidateStruct(v interface{}) porterr.IError {
-	var e porterr.IError
 	ve := reflect.ValueOf(v)
-	te := reflect.TypeOf(v)
-
 	if ve.Kind() == reflect.Ptr {
 		ve = ve.Elem()
-		te = te.Elem()
 	}
-
 	if ve.Kind() != reflect.Struct {
-		if e == nil {
-			e = porterr.HttpValidationError()
-		}
+		e := porterr.HttpValidationError()
 		e = e.PushDetail(porterr.PortErrorParam, "type", "Type struct required. Type "+ve.Kind().String()+" received")
 		return e
 	}
+	vl := newValidator()
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+		vl.enter(rv, "")
+	}
+	if ce := vl.validateStruct(ve, reflect.Value{}, ""); ce != nil {
+		return ce
+	}
+	return vl.e.IfDetails()
+}
 
-	var fieldName string
-	var rules ValidationRules
-
-	var f reflect.Value
-	var t reflect.StructField
+// ValidateUpdate validate new version of struct and compare it with old version by update rules
+// Old version could be nil, then update rules are skipped and only validation rules are applied
+func ValidateUpdate(old interface{}, v interface{}) porterr.IError {
+	ve := reflect.ValueOf(v)
+	if ve.Kind() == reflect.Ptr {
+		ve = ve.Elem()
+	}
+	oe := reflect.ValueOf(old)
+	if oe.Kind() == reflect.Ptr {
+		oe = oe.Elem()
+	}
+	if ve.Kind() != reflect.Struct {
+		e := porterr.HttpValidationError()
+		e = e.PushDetail(porterr.PortErrorParam, "type", "Type struct required. Type "+ve.Kind().String()+" received")
+		return e
+	}
+	if oe.IsValid() && oe.Type() != ve.Type() {
+		e := porterr.HttpValidationError()
+		e = e.PushDetail(porterr.PortErrorParam, "type", "Type "+ve.Type().String()+" of old version required. Type "+oe.Type().String()+" received")
+		return e
+	}
+	vl := newValidator()
+	vl.update = true
+	if ce := vl.validateStruct(ve, oe, ""); ce != nil {
+		return ce
+	}
+	return vl.e.IfDetails()
+}
 
-	for i := 0; i < ve.NumField(); i++ {
-		f = ve.Field(i)
-		t = te.Field(i)
-		validTag := t.Tag.Get("valid")
-		if validTag == "-" {
-			continue
-		}
-		switch f.Kind() {
-		case reflect.Struct:
-			if e == nil {
-				e = porterr.HttpValidationError()
-			}
-			e = e.MergeDetails(ValidateStruct(f.Interface()))
-		case reflect.Slice:
-			for j := 0; j < f.Len(); j++ {
-				if f.Index(j).Kind() == reflect.Struct || f.Index(j).Kind() == reflect.Ptr {
-					if e == nil {
-						e = porterr.HttpValidationError()
-					}
-					e = e.MergeDetails(ValidateStruct(f.Index(j).Interface()))
-				}
+// IsRuleValid check value with rule. Alternatives and negation are applied
+// Unknown rules are ignored
+func IsRuleValid(val reflect.Value, rule ValidationRule) bool {
+	var valid bool
+	if len(rule.Alternatives) > 0 {
+		for _, alternative := range rule.Alternatives {
+			if valid = IsRulesValid(val, alternative); valid {
+				break
 			}
-		case reflect.Ptr:
-			if !f.IsNil() {
-				if f.Elem().Kind() == reflect.Slice {
-					for j := 0; j < f.Elem().Len(); j++ {
-						if f.Elem().Index(j).Kind() == reflect.Struct || f.Elem().Index(j).Kind() == reflect.Ptr {
-							if e == nil {
-								e = porterr.HttpValidationError()
-							}
-							e = e.MergeDetails(ValidateStruct(f.Elem().Index(j).Interface()))
-						}
-					}
-				} else if f.Elem().Kind() == reflect.Struct && f.Elem().CanInterface() {
-					if e == nil {
-						e = porterr.HttpValidationError()
-					}
-					e = e.MergeDetails(ValidateStruct(f.Interface()))
-				}
-			}
-		}
-		fieldName = t.Tag.Get("json")
-		if fieldName == "" {
-			fieldName = t.Name
 		}
-		rules = ParseValidTag(validTag)
-		for _, rule := range rules {
-			if vRule, ok := actualValidationRules[rule.Name]; ok {
-				if !vRule(f, rule.Args...) {
-					if e == nil {
-						e = porterr.HttpValidationError()
-					}
-					e = e.PushDetail(porterr.PortErrorParam, fieldName, "Invalid validation for "+rule.Name+" rule on field: "+fieldName)
-				}
-			}
+	} else {
+		vRule, ok := actualValidationRules[rule.Name]
+		if !ok {
+			return true
 		}
+		valid = vRule(val, rule.Args...)
 	}
-	if e == nil {
-		return nil
-	}
-	return e.IfDetails()
+	return valid != rule.Not
 }
 
-// ParseValidTag parse validation tag for rule and arguments
-// Example
-// valid:"rx~[0-5]+;range~1-50;enum~5,10,15,20,25"`
-func ParseValidTag(validTag string) ValidationRules {
-	if validTag == "" {
-		return nil
-	}
-	var result = make(ValidationRules, 4)
-	var ruleCount int
-	var indexStart, i int
+// IsRulesValid check value with all rules
+func IsRulesValid(val reflect.Value, rules ValidationRules) bool {
+	for _, rule := range rules {
+		if !IsRuleValid(val, rule) {
+			return false
+		}
+	}
+	return true
+}
 
-	for {
-		if validTag[i] == ';' && result[ruleCount].Name == "" {
-			result[ruleCount].Name = validTag[indexStart:i]
-			ruleCount++
-			indexStart = i + 1
+// ruleErrorMessage prepare error message for invalid rule
+// Message of enum rules contains allowed values for the type of value
+func ruleErrorMessage(rule ValidationRule, fieldName string, t reflect.Type) string {
+	if len(rule.Alternatives) == 0 || rule.Alias != "" {
+		if rule.Alias != "" {
+			rule.Name = rule.Alias
 		}
-		if validTag[i] == '~' {
-			if ruleCount == len(result) {
-				result = append(result, make(ValidationRules, 4)...)
-			}
-			result[ruleCount].Name = validTag[indexStart:i]
-			i++
-			indexStart = i
-			for {
-				if validTag[i] == ';' {
-					result[ruleCount].Args = []string{validTag[indexStart:i]}
-					ruleCount++
-					indexStart = i + 1
-					break
-				}
-				i++
-				if i >= len(validTag) {
-					break
-				}
-			}
+		if rule.Not {
+			return "Invalid validation for " + string(tagNot) + rule.Name + " rule on field: " + fieldName
 		}
-		i++
-		if i >= len(validTag) {
-			break
+		if rule.Alias == "" && (rule.Name == "enum" || rule.Name == "ienum") {
+			return "Invalid validation for " + rule.Name + " rule on field: " + fieldName + enumMessage(t, rule.Args)
 		}
+		return "Invalid validation for " + rule.Name + " rule on field: " + fieldName
 	}
-
-	if validTag[len(validTag)-1] != ';' {
-		if result[ruleCount].Name == "" {
-			result[ruleCount].Name = validTag[indexStart:i]
+	if rule.Not {
+		return "Invalid validation for " + rule.String() + " rule on field: " + fieldName + ". Negated group must not be valid"
+	}
+	var message = "Invalid validation on field: " + fieldName + ". None of alternatives is valid: "
+	for i, alternative := range rule.Alternatives {
+		if i > 0 {
+			message += ", "
+		}
+		if len(alternative) > 1 {
+			message += string(tagGroupStart) + alternative.String() + string(tagGroupEnd)
 		} else {
-			result[ruleCount].Args = []string{validTag[indexStart:]}
+			message += alternative.String()
 		}
-		ruleCount++
 	}
-
-	return result[:ruleCount]
+	return message
 }
 
 // Init default validators
 func init() {
 	PrepareActualValidationRules(nil)
+	PrepareUpdateValidationRules(nil)
 }
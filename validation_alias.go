@@ -0,0 +1,81 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"strings"
+)
+
+// Registered rule aliases. Alias name -> rules in tag notation
+var aliases = make(map[string]string)
+
+// RegisterAlias register named alias for the rules
+// Alias could be used in valid tag as a rule and could contain other aliases
+// Example
+// v.RegisterAlias("username", "required;min~3;max~32;rx~^[a-z0-9_]+$")
+// valid:"username"
+func RegisterAlias(name string, rules string) porterr.IError {
+	if !validName(name) {
+		return porterr.New(porterr.PortErrorArgument, "Invalid alias name '"+name+"'")
+	}
+	if _, ok := actualValidationRules[name]; ok {
+		return porterr.New(porterr.PortErrorArgument, "Alias '"+name+"' conflicts with validation rule")
+	}
+	if _, ok := actualUpdateRules[name]; ok {
+		return porterr.New(porterr.PortErrorArgument, "Alias '"+name+"' conflicts with update rule")
+	}
+	if isGroupRule(name) || name == ruleDive || name == ruleUnion {
+		return porterr.New(porterr.PortErrorArgument, "Alias '"+name+"' conflicts with reserved rule")
+	}
+	if _, e := parseValidTag(rules); e != nil {
+		return e
+	}
+	aliases[name] = rules
+	resetPlans()
+	return nil
+}
+
+// UnregisterAlias remove registered alias
+func UnregisterAlias(name string) {
+	delete(aliases, name)
+	resetPlans()
+}
+
+// expandAliases replace aliases in rules with its rules
+// stack contains aliases being expanded and used for cycle detection
+func expandAliases(rules ValidationRules, stack []string) (ValidationRules, porterr.IError) {
+	var e porterr.IError
+	for i := range rules {
+		if len(rules[i].Alternatives) > 0 {
+			for j := range rules[i].Alternatives {
+				rules[i].Alternatives[j], e = expandAliases(rules[i].Alternatives[j], stack)
+				if e != nil {
+					return nil, e
+				}
+			}
+			continue
+		}
+		tag, ok := aliases[rules[i].Name]
+		if !ok {
+			continue
+		}
+		name := rules[i].Name
+		for _, s := range stack {
+			if s == name {
+				return nil, porterr.New(porterr.PortErrorRecursion, "Alias cycle detected: "+strings.Join(append(stack, name), " -> "))
+			}
+		}
+		if len(rules[i].Args) > 0 {
+			return nil, porterr.New(porterr.PortErrorArgument, "Alias '"+name+"' does not accept arguments")
+		}
+		aliasRules, e := parseValidTag(tag)
+		if e != nil {
+			return nil, e
+		}
+		aliasRules, e = expandAliases(aliasRules, append(stack, name))
+		if e != nil {
+			return nil, e
+		}
+		rules[i] = ValidationRule{Alias: name, Not: rules[i].Not, Alternatives: []ValidationRules{aliasRules}}
+	}
+	return rules, nil
+}
@@ -0,0 +1,215 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// argsChecker check rule arguments for the type of value on plan compile
+type argsChecker func(t reflect.Type, args ...string) porterr.IError
+
+// Checkers of basic rules arguments
+var argsCheckers = map[string]argsChecker{
+	"min":        checkBoundArgs,
+	"max":        checkBoundArgs,
+	"gt":         checkBoundArgs,
+	"lt":         checkBoundArgs,
+	"gte":        checkBoundArgs,
+	"lte":        checkBoundArgs,
+	"minItems":   checkLengthArgs,
+	"maxItems":   checkLengthArgs,
+	"len":        checkLengthArgs,
+	"digit":      checkLengthArgs,
+	"before":     checkTimeArgs,
+	"after":      checkTimeArgs,
+	"between":    checkTimeArgs,
+	"maxAge":     checkDurationArgs,
+	"range":      checkRangeArgs,
+	"enum":       checkEnumArgs,
+	"ienum":      checkEnumArgs,
+	"transition": checkTransitionArgs,
+	"scale":      checkLengthArgs,
+	"multipleOf": func(t reflect.Type, args ...string) porterr.IError {
+		if m, ok := ParseDecimal(args[0]); !ok || m.Sign() <= 0 {
+			return porterr.New(porterr.PortErrorArgument, "Invalid multiple "+args[0])
+		}
+		return nil
+	},
+	"precision": func(t reflect.Type, args ...string) porterr.IError {
+		if _, _, ok := parsePrecision(args[0]); !ok {
+			return porterr.New(porterr.PortErrorArgument, "Invalid precision "+args[0])
+		}
+		return nil
+	},
+}
+
+// checkRulesArgs check arguments of rules including alternatives
+// Rules overridden by custom validation rules are not checked
+func checkRulesArgs(rules ValidationRules, t reflect.Type) porterr.IError {
+	for _, rule := range rules {
+		for _, alternative := range rule.Alternatives {
+			if e := checkRulesArgs(alternative, t); e != nil {
+				return e
+			}
+		}
+		checker, ok := argsCheckers[rule.Name]
+		if !ok || len(rule.Args) == 0 {
+			continue
+		}
+		if !isBasicRule(rule.Name) {
+			continue
+		}
+		if e := checker(t, rule.Args...); e != nil {
+			return porterr.New(porterr.PortErrorArgument, "Rule "+rule.String()+". "+e.Error())
+		}
+	}
+	return nil
+}
+
+// isBasicRule check if rule is basic validation or update rule not overridden by custom rule
+func isBasicRule(name string) bool {
+	if callback, ok := basicValidationRules[name]; ok {
+		return reflect.ValueOf(callback).Pointer() == reflect.ValueOf(actualValidationRules[name]).Pointer()
+	}
+	if callback, ok := basicUpdateRules[name]; ok {
+		return reflect.ValueOf(callback).Pointer() == reflect.ValueOf(actualUpdateRules[name]).Pointer()
+	}
+	return false
+}
+
+// checkBoundArgs check if bound could be parsed and fits the type
+func checkBoundArgs(t reflect.Type, args ...string) porterr.IError {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	bound := args[0]
+	if UseTextValues && isTextType(t) {
+		return checkLengthArgs(t, args...)
+	}
+	if isBigType(t) {
+		if _, ok := ParseDecimal(bound); !ok {
+			return invalidBound(t, bound)
+		}
+		return nil
+	}
+	if t == durationType {
+		if _, err := ParseDuration(bound); err != nil {
+			return porterr.New(porterr.PortErrorArgument, "Invalid duration "+bound)
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return checkBoundArgs(t.Elem(), args...)
+	case reflect.String:
+		return checkLengthArgs(t, args...)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(bound, 10, t.Bits()); err == nil {
+			return nil
+		} else if err.(*strconv.NumError).Err == strconv.ErrRange {
+			return outOfRange(t, bound)
+		}
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return invalidBound(t, bound)
+		}
+		if f < -math.Pow(2, float64(t.Bits()-1)) || f >= math.Pow(2, float64(t.Bits()-1)) {
+			return outOfRange(t, bound)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(bound, 10, t.Bits()); err == nil {
+			return nil
+		} else if err.(*strconv.NumError).Err == strconv.ErrRange {
+			return outOfRange(t, bound)
+		}
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return invalidBound(t, bound)
+		}
+		if f < 0 || f >= math.Pow(2, float64(t.Bits())) {
+			return outOfRange(t, bound)
+		}
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(bound, t.Bits())
+		if err != nil {
+			if err.(*strconv.NumError).Err == strconv.ErrRange {
+				return outOfRange(t, bound)
+			}
+			return invalidBound(t, bound)
+		}
+		if math.IsNaN(f) {
+			return invalidBound(t, bound)
+		}
+	}
+	return nil
+}
+
+// checkRangeArgs check if range could be parsed and bounds fit the type
+func checkRangeArgs(t reflect.Type, args ...string) porterr.IError {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	intervals, ok := parseRange(args[0])
+	if !ok {
+		return porterr.New(porterr.PortErrorArgument, "Invalid range "+args[0])
+	}
+	for _, i := range intervals {
+		for _, bound := range i.bounds() {
+			var e porterr.IError
+			switch {
+			case t.Kind() == reflect.String && !isBigType(t):
+			case t.Kind() == reflect.Struct && t.ConvertibleTo(timeType):
+				e = checkTimeArgs(t, bound)
+			default:
+				e = checkBoundArgs(t, bound)
+			}
+			if e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// checkLengthArgs check if lengths separated by comma are not negative integers
+func checkLengthArgs(t reflect.Type, args ...string) porterr.IError {
+	for _, length := range strings.Split(args[0], ",") {
+		l, err := strconv.Atoi(length)
+		if err != nil || l < 0 {
+			return porterr.New(porterr.PortErrorArgument, "Invalid length "+length)
+		}
+	}
+	return nil
+}
+
+// checkTimeArgs check if times separated by comma could be parsed
+func checkTimeArgs(t reflect.Type, args ...string) porterr.IError {
+	for _, arg := range strings.Split(args[0], ",") {
+		if _, err := ParseTime(arg, time.Time{}); err != nil {
+			return porterr.New(porterr.PortErrorArgument, "Invalid time "+arg)
+		}
+	}
+	return nil
+}
+
+// checkDurationArgs check if duration could be parsed
+func checkDurationArgs(t reflect.Type, args ...string) porterr.IError {
+	if _, err := ParseDuration(args[0]); err != nil {
+		return porterr.New(porterr.PortErrorArgument, "Invalid duration "+args[0])
+	}
+	return nil
+}
+
+// outOfRange prepare out of range bound error
+func outOfRange(t reflect.Type, bound string) porterr.IError {
+	return porterr.New(porterr.PortErrorArgument, "Bound "+bound+" is out of range of type "+t.String())
+}
+
+// invalidBound prepare invalid bound error
+func invalidBound(t reflect.Type, bound string) porterr.IError {
+	return porterr.New(porterr.PortErrorArgument, "Invalid bound "+bound+" for type "+t.String())
+}
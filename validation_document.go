@@ -0,0 +1,164 @@
+package v
+
+import (
+	"encoding/json"
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DocumentSchema rules of json document fields by path
+// Fields of nested objects are separated by dot, items of arrays are marked with []
+// Example
+//
+//	v.DocumentSchema{
+//		"name":        "required;min~3",
+//		"address.zip": "required;digit~5",
+//		"items":       "minItems~1",
+//		"items[].qty": "required;min~1",
+//	}
+type DocumentSchema map[string]string
+
+// Array items marker in document path
+const documentItems = "[]"
+
+// Type of document values
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// documentNode compiled rules of document value
+type documentNode struct {
+	// Rules of the value
+	rules valuePlan
+	// Fields of object
+	fields map[string]*documentNode
+	// Items of array
+	items *documentNode
+}
+
+// ValidateMap validate object decoded from json with document schema
+// Missing fields are validated as null, fields of missing objects are not validated
+func ValidateMap(document map[string]interface{}, schema DocumentSchema) porterr.IError {
+	return validateDocument(reflect.ValueOf(document), schema)
+}
+
+// ValidateJSON validate raw json object with document schema
+func ValidateJSON(data json.RawMessage, schema DocumentSchema) porterr.IError {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		e := porterr.HttpValidationError()
+		e = e.PushDetail(porterr.PortErrorParam, "body", "Invalid json: "+err.Error())
+		return e
+	}
+	return validateDocument(reflect.ValueOf(document), schema)
+}
+
+// validateDocument validate document value with schema
+func validateDocument(document reflect.Value, schema DocumentSchema) porterr.IError {
+	if document.Kind() != reflect.Map || document.Type().Key().Kind() != reflect.String {
+		kind := "null"
+		if document.IsValid() {
+			kind = document.Kind().String()
+		}
+		e := porterr.HttpValidationError()
+		e = e.PushDetail(porterr.PortErrorParam, "type", "Type object required. Type "+kind+" received")
+		return e
+	}
+	root, e := compileDocument(schema)
+	if e != nil {
+		return e
+	}
+	vl := newValidator()
+	vl.validateObject(document, "", root)
+	return vl.e.IfDetails()
+}
+
+// compileDocument compile rules of document schema to tree of nodes
+func compileDocument(schema DocumentSchema) (*documentNode, porterr.IError) {
+	root := &documentNode{}
+	var paths = make([]string, 0, len(schema))
+	for path := range schema {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		tag := schema[path]
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			var items int
+			for strings.HasSuffix(name, documentItems) {
+				name = strings.TrimSuffix(name, documentItems)
+				items++
+			}
+			if name == "" {
+				return nil, porterr.New(porterr.PortErrorParser, "Path "+path+". Field name expected")
+			}
+			if node.fields == nil {
+				node.fields = make(map[string]*documentNode)
+			}
+			if node.fields[name] == nil {
+				node.fields[name] = &documentNode{}
+			}
+			node = node.fields[name]
+			for ; items > 0; items-- {
+				if node.items == nil {
+					node.items = &documentNode{}
+				}
+				node = node.items
+			}
+		}
+		rules, e := compileRules(tag)
+		if e == nil {
+			node.rules, e = compileValuePlan(rules, interfaceType)
+		}
+		if e != nil {
+			return nil, porterr.New(porterr.PortErrorParser, "Path "+path+". "+e.Error())
+		}
+	}
+	return root, nil
+}
+
+// validateObject validate fields of object with nodes of schema
+func (vl *validator) validateObject(object reflect.Value, path string, node *documentNode) {
+	var names = make([]string, 0, len(node.fields))
+	for name := range node.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := object.MapIndex(reflect.ValueOf(name).Convert(object.Type().Key()))
+		if !value.IsValid() {
+			value = reflect.Zero(interfaceType)
+		}
+		vl.validateDocumentValue(value, fieldPath(path, name), node.fields[name])
+	}
+}
+
+// validateDocumentValue apply rules to document value and validate its fields or items
+// Rules see value as pointer, so null and missing values are nil pointers
+func (vl *validator) validateDocumentValue(value reflect.Value, path string, node *documentNode) {
+	value = elemValue(value)
+	vl.validateValue(value, reflect.Value{}, path, &node.rules)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if node.fields != nil {
+		if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+			vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid type of field: "+path+". Object required")
+			return
+		}
+		vl.validateObject(value, path, node)
+	}
+	if node.items != nil {
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid type of field: "+path+". Array required")
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			vl.validateDocumentValue(value.Index(i), indexName(path, i), node.items)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Prefix of registered enum reference in enum rule argument
+// Example: enum~@OrderStatus
+const enumRef = "@"
+
+// Registered enums. Enum name -> allowed values in text form
+var enums = make(map[string][]string)
+
+// Enumer type that provides its allowed values
+// Rule enum without arguments uses values of the field type implementing Enumer
+type Enumer[T any] interface {
+	// Values allowed values of the type
+	Values() []T
+}
+
+// RegisterEnum register named set of allowed values
+// Set could be used in enum rule by name with @ prefix
+// Example
+// v.RegisterEnum("OrderStatus", "draft", "paid", "shipped")
+// valid:"enum~@OrderStatus"
+func RegisterEnum[T any](name string, values ...T) porterr.IError {
+	if !validName(name) {
+		return porterr.New(porterr.PortErrorArgument, "Invalid enum name '"+name+"'")
+	}
+	if len(values) == 0 {
+		return porterr.New(porterr.PortErrorArgument, "Enum '"+name+"' has no values")
+	}
+	var list = make([]string, len(values))
+	for i := range values {
+		list[i] = enumString(reflect.ValueOf(values[i]))
+	}
+	enums[name] = list
+	resetPlans()
+	return nil
+}
+
+// UnregisterEnum remove registered enum
+func UnregisterEnum(name string) {
+	delete(enums, name)
+	resetPlans()
+}
+
+// enumValues allowed values for the type from rule arguments
+// Literal values separated by comma, reference to registered enum or values of Enumer type
+// Returns false if there is no source of values
+func enumValues(t reflect.Type, args []string) ([]string, bool) {
+	if len(args) == 0 || args[0] == "" {
+		return enumerValues(t)
+	}
+	if strings.HasPrefix(args[0], enumRef) {
+		values, ok := enums[strings.TrimPrefix(args[0], enumRef)]
+		return values, ok
+	}
+	return strings.Split(args[0], ","), true
+}
+
+// enumerValues values of type implementing Enumer
+func enumerValues(t reflect.Type) ([]string, bool) {
+	method := reflect.New(t).MethodByName("Values")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 || method.Type().Out(0).Kind() != reflect.Slice {
+		return nil, false
+	}
+	items := method.Call(nil)[0]
+	var values = make([]string, items.Len())
+	for i := range values {
+		values[i] = enumString(items.Index(i))
+	}
+	return values, true
+}
+
+// enumString text of enum value as it is compared by enum rule
+func enumString(val reflect.Value) string {
+	if !val.IsValid() {
+		return ""
+	}
+	val = textValue(val)
+	if r, ok := bigNumber(val); ok {
+		if r == nil {
+			return ""
+		}
+		if _, scale, ok := decimalDigits(r); ok {
+			return r.FloatString(scale)
+		}
+		return r.RatString()
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return val.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool())
+	}
+	return ""
+}
+
+// enumMessage list of allowed values for error message of enum rule
+func enumMessage(t reflect.Type, args []string) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if t.Kind() != reflect.Ptr && isTextType(t) && UseTextValues {
+			break
+		}
+		t = t.Elem()
+	}
+	values, ok := enumValues(t, args)
+	if !ok {
+		return ""
+	}
+	return ". Allowed values: " + strings.Join(values, ", ")
+}
+
+// checkEnumArgs check if referenced enum is registered
+func checkEnumArgs(t reflect.Type, args ...string) porterr.IError {
+	if !strings.HasPrefix(args[0], enumRef) {
+		return nil
+	}
+	if _, ok := enums[strings.TrimPrefix(args[0], enumRef)]; !ok {
+		return porterr.New(porterr.PortErrorArgument, "Enum "+args[0]+" is not registered")
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strings"
+)
+
+// Group constraints. Rule argument is the name of group
+// Example: valid:"oneOf~payment"
+const (
+	// Exactly one field of group must be set
+	groupOneOf = "oneOf"
+	// At least one field of group must be set
+	groupAnyOf = "anyOf"
+	// Not more than one field of group could be set
+	groupExclusive = "exclusive"
+)
+
+// groupPlan compiled group constraint of struct fields
+type groupPlan struct {
+	// Group rule
+	rule string
+	// Group name used in errors
+	name string
+	// Indexes of member fields in struct
+	fields []int
+	// Names of member fields used in errors
+	names []string
+}
+
+// groupRegistration group constraint registered for struct type
+type groupRegistration struct {
+	// Group rule
+	rule string
+	// Group name
+	name string
+	// Go names of member fields
+	fields []string
+}
+
+// Registered group constraints. Struct type -> groups
+var registeredGroups = make(map[reflect.Type][]groupRegistration)
+
+// RegisterGroup register group constraint of struct fields without tags
+// Rule is one of oneOf, anyOf or exclusive, fields are go names of struct fields
+// Example
+// v.RegisterGroup(Payment{}, "oneOf", "payment", "CardToken", "BankAccount", "Wallet")
+func RegisterGroup(v interface{}, rule string, name string, fields ...string) porterr.IError {
+	te := reflect.TypeOf(v)
+	for te != nil && te.Kind() == reflect.Ptr {
+		te = te.Elem()
+	}
+	if te == nil || te.Kind() != reflect.Struct {
+		return porterr.New(porterr.PortErrorArgument, "Type struct required")
+	}
+	if !isGroupRule(rule) {
+		return porterr.New(porterr.PortErrorArgument, "Unknown group rule '"+rule+"'")
+	}
+	if !validName(name) {
+		return porterr.New(porterr.PortErrorArgument, "Invalid group name '"+name+"'")
+	}
+	if len(fields) == 0 {
+		return porterr.New(porterr.PortErrorArgument, "Group fields are required")
+	}
+	for _, field := range fields {
+		if f, ok := te.FieldByName(field); !ok || len(f.Index) != 1 {
+			return porterr.New(porterr.PortErrorArgument, "Field "+te.String()+"."+field+" not found")
+		}
+	}
+	registeredGroups[te] = append(registeredGroups[te], groupRegistration{rule: rule, name: name, fields: fields})
+	resetPlans()
+	return nil
+}
+
+// UnregisterGroups remove all registered group constraints of struct type
+func UnregisterGroups(v interface{}) {
+	te := reflect.TypeOf(v)
+	for te != nil && te.Kind() == reflect.Ptr {
+		te = te.Elem()
+	}
+	delete(registeredGroups, te)
+	resetPlans()
+}
+
+// isGroupRule check if rule is group constraint
+func isGroupRule(name string) bool {
+	return name == groupOneOf || name == groupAnyOf || name == groupExclusive
+}
+
+// containsGroupRule check if group rule is used inside alternatives
+func containsGroupRule(alternatives []ValidationRules) (string, bool) {
+	for _, alternative := range alternatives {
+		for _, rule := range alternative {
+			if isGroupRule(rule.Name) {
+				return rule.Name, true
+			}
+			if name, ok := containsGroupRule(rule.Alternatives); ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// addGroups remove group rules from field rules and add the field to groups of struct plan
+func (plan *structPlan) addGroups(rules ValidationRules, index int, name string) (ValidationRules, porterr.IError) {
+	var fieldRules = make(ValidationRules, 0, len(rules))
+	for _, rule := range rules {
+		if group, ok := containsGroupRule(rule.Alternatives); ok {
+			return nil, porterr.New(porterr.PortErrorParser, "Rule "+group+" is not allowed in alternatives, groups and aliases")
+		}
+		if !isGroupRule(rule.Name) {
+			fieldRules = append(fieldRules, rule)
+			continue
+		}
+		if rule.Not {
+			return nil, porterr.New(porterr.PortErrorParser, "Rule "+rule.Name+" could not be negated")
+		}
+		if len(rule.Args) == 0 || rule.Args[0] == "" {
+			return nil, porterr.New(porterr.PortErrorParser, "Rule "+rule.Name+" requires group name")
+		}
+		if e := plan.addGroupField(rule.Name, rule.Args[0], index, name); e != nil {
+			return nil, e
+		}
+	}
+	return fieldRules, nil
+}
+
+// addGroupField add field to the group of struct plan
+func (plan *structPlan) addGroupField(rule string, group string, index int, name string) porterr.IError {
+	for i := range plan.groups {
+		if plan.groups[i].name != group {
+			continue
+		}
+		if plan.groups[i].rule != rule {
+			return porterr.New(porterr.PortErrorParser, "Group "+group+" is declared with rules "+plan.groups[i].rule+" and "+rule)
+		}
+		for _, field := range plan.groups[i].fields {
+			if field == index {
+				return nil
+			}
+		}
+		plan.groups[i].fields = append(plan.groups[i].fields, index)
+		plan.groups[i].names = append(plan.groups[i].names, name)
+		return nil
+	}
+	plan.groups = append(plan.groups, groupPlan{rule: rule, name: group, fields: []int{index}, names: []string{name}})
+	return nil
+}
+
+// addRegisteredGroups add groups registered for struct type to the plan
+func (plan *structPlan) addRegisteredGroups(te reflect.Type) porterr.IError {
+	for _, group := range registeredGroups[te] {
+		for _, field := range group.fields {
+			t, ok := te.FieldByName(field)
+			if !ok {
+				return porterr.New(porterr.PortErrorParser, "Group "+group.name+". Field "+field+" not found")
+			}
+			name, _ := fieldName(t)
+			if e := plan.addGroupField(group.rule, group.name, t.Index[0], name); e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// validateGroups check group constraints of struct and push error details
+func (vl *validator) validateGroups(ve reflect.Value, path string, groups []groupPlan) {
+	for _, group := range groups {
+		var count int
+		for _, index := range group.fields {
+			if isValueSet(exportedValue(ve.Field(index))) {
+				count++
+			}
+		}
+		var message string
+		switch {
+		case group.rule == groupOneOf && count != 1:
+			message = "Exactly one of " + strings.Join(group.names, ", ") + " must be set"
+		case group.rule == groupAnyOf && count == 0:
+			message = "At least one of " + strings.Join(group.names, ", ") + " must be set"
+		case group.rule == groupExclusive && count > 1:
+			message = "Only one of " + strings.Join(group.names, ", ") + " could be set"
+		default:
+			continue
+		}
+		name := fieldPath(path, group.name)
+		vl.e.PushDetail(porterr.PortErrorParam, name, "Invalid validation for "+group.rule+" rule on group: "+name+". "+message)
+	}
+}
+
+// isValueSet check if value is set. Pointers are set when they are not nil, other values when they are not zero
+func isValueSet(f reflect.Value) bool {
+	f = UnwrapValue(f)
+	switch f.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !f.IsNil()
+	case reflect.Slice, reflect.Map:
+		return f.Len() > 0
+	}
+	return !f.IsZero()
+}
@@ -0,0 +1,277 @@
+package v
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"github.com/dimonrus/porterr"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag of parameter name in query and form. Json name of field is used when tag is not set
+const formTag = "form"
+
+// MaxMemory maximum memory used by multipart form. Rest of files are stored on disk
+var MaxMemory int64 = 32 << 20
+
+// MaxBodySize maximum size of request body in bytes. Larger body is reported with 413 http code
+// Zero or negative size disables the limit
+var MaxBodySize int64 = 32 << 20
+
+// StrictBinding report json fields which are not present in struct
+var StrictBinding = false
+
+// ErrorWriter write error response of request binding
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, e porterr.IError)
+
+// WriteError writer of binding errors used by Handle and Middleware
+// Error is written as json with http code of the error by default
+var WriteError ErrorWriter = writeJSONError
+
+// Type of multipart file
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
+// boundKey context key of value bound by Middleware
+type boundKey[T any] struct{}
+
+// Bind bind query parameters and request body into struct and validate it
+// Body is decoded by content type: json, urlencoded form or multipart form
+// Parameters of query and form are matched by form tag or json name of field
+// Fields with json tag "-" are not bound without form tag
+// Example
+//
+//	type Search struct {
+//		Query string                `json:"query" form:"q" valid:"required"`
+//		Page  int                   `json:"page" valid:"min~1"`
+//		File  *multipart.FileHeader `json:"-" form:"file"`
+//	}
+func Bind(r *http.Request, dst interface{}) porterr.IError {
+	return bind(nil, r, dst)
+}
+
+// bind bind request into struct and validate it
+// Response writer is used by http.MaxBytesReader to close connection when body is too large
+func bind(w http.ResponseWriter, r *http.Request, dst interface{}) porterr.IError {
+	ve, e := structPointer(dst)
+	if e != nil {
+		return e
+	}
+	vl := newValidator()
+	if e = vl.bindRequest(w, r, ve.Elem()); e != nil {
+		return e
+	}
+	return vl.validateDecoded(ve.Elem())
+}
+
+// Handle bind request into value of type T and pass it to handler
+// Errors of binding and validation are written by WriteError
+// Example
+// http.Handle("/orders", v.Handle(func(w http.ResponseWriter, r *http.Request, order *Order) { ... }))
+func Handle[T any](handler func(w http.ResponseWriter, r *http.Request, v *T)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v := new(T)
+		if e := bind(w, r, v); e != nil {
+			WriteError(w, r, e)
+			return
+		}
+		handler(w, r, v)
+	}
+}
+
+// Middleware bind request into value of type T and store it in request context
+// Value is available in next handler by Bound
+func Middleware[T any](next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := new(T)
+		if e := bind(w, r, v); e != nil {
+			WriteError(w, r, e)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), boundKey[T]{}, v)))
+	})
+}
+
+// Bound get value of type T bound by Middleware
+func Bound[T any](ctx context.Context) (*T, bool) {
+	v, ok := ctx.Value(boundKey[T]{}).(*T)
+	return v, ok
+}
+
+// writeJSONError write error as json with http code of the error
+func writeJSONError(w http.ResponseWriter, r *http.Request, e porterr.IError) {
+	code := e.GetHTTP()
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// bindRequest bind query parameters and body into struct and push error details of values which could not be bound
+// Returns error if body is malformed, too large or content type is not supported
+func (vl *validator) bindRequest(w http.ResponseWriter, r *http.Request, ve reflect.Value) porterr.IError {
+	vl.bindValues(ve, r.URL.Query(), nil)
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	if MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBodySize)
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case contentType == "application/json" || strings.HasSuffix(contentType, "+json"):
+		return vl.decodeJSON(r.Body, ve, StrictBinding)
+	case contentType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return bodyError("Invalid form: ", err)
+		}
+		vl.bindValues(ve, r.PostForm, nil)
+	case contentType == "multipart/form-data":
+		if err := r.ParseMultipartForm(MaxMemory); err != nil {
+			return bodyError("Invalid form: ", err)
+		}
+		vl.bindValues(ve, r.MultipartForm.Value, r.MultipartForm.File)
+	default:
+		return porterr.New(porterr.PortErrorRequest, "Content type '"+contentType+"' is not supported").HTTP(http.StatusUnsupportedMediaType)
+	}
+	return nil
+}
+
+// bodyError prepare error of request body which could not be read or parsed
+// Body larger than MaxBodySize is reported with 413 http code
+func bodyError(message string, err error) porterr.IError {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return porterr.New(porterr.PortErrorRequest, "Request body is too large. Maximum size is "+
+			strconv.FormatInt(maxBytesError.Limit, 10)+" bytes").HTTP(http.StatusRequestEntityTooLarge)
+	}
+	e := porterr.HttpValidationError()
+	return e.PushDetail(porterr.PortErrorParam, "body", message+err.Error())
+}
+
+// bindValues set fields of struct from query or form values and files
+// Fields hidden from json by "-" are bound only with explicit form tag
+// Errors are reported with json name of field as validation errors are. Fields of embedded structs are promoted
+func (vl *validator) bindValues(ve reflect.Value, values map[string][]string, files map[string][]*multipart.FileHeader) {
+	te := ve.Type()
+	for i := 0; i < te.NumField(); i++ {
+		field := te.Field(i)
+		name := field.Tag.Get(formTag)
+		if name == "-" || name == "" && field.Tag.Get("json") == "-" {
+			continue
+		}
+		path, promote := fieldName(field)
+		if name == "" {
+			name = path
+		}
+		f := ve.Field(i)
+		if promote {
+			if f.Kind() == reflect.Ptr {
+				if f.IsNil() {
+					if !f.CanSet() {
+						continue
+					}
+					f.Set(reflect.New(f.Type().Elem()))
+				}
+				f = f.Elem()
+			}
+			vl.bindValues(f, values, files)
+			continue
+		}
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Type() == fileHeaderType:
+			if len(files[name]) > 0 {
+				f.Set(reflect.ValueOf(files[name][0]))
+			}
+		case f.Kind() == reflect.Slice && f.Type().Elem() == fileHeaderType:
+			if len(files[name]) > 0 {
+				f.Set(reflect.ValueOf(files[name]))
+			}
+		default:
+			if len(values[name]) > 0 {
+				vl.bindStrings(f, values[name], path)
+			}
+		}
+	}
+}
+
+// bindStrings set value from parameter values. Slices get all values, other types get the first one
+func (vl *validator) bindStrings(f reflect.Value, values []string, path string) {
+	if f.Kind() != reflect.Slice || f.Type().Elem().Kind() == reflect.Uint8 || reflect.PtrTo(f.Type()).Implements(textUnmarshalerType) {
+		vl.bindString(f, values[0], path)
+		return
+	}
+	slice := reflect.MakeSlice(f.Type(), len(values), len(values))
+	for i := range values {
+		vl.bindString(slice.Index(i), values[i], indexName(path, i))
+	}
+	f.Set(slice)
+}
+
+// bindString set value parsed from string according to its kind
+// Values of types implementing encoding.TextUnmarshaler are parsed by UnmarshalText, durations by time.ParseDuration
+func (vl *validator) bindString(f reflect.Value, s string, path string) {
+	if f.Kind() == reflect.Ptr {
+		value := reflect.New(f.Type().Elem())
+		vl.bindString(value.Elem(), s, path)
+		f.Set(value)
+		return
+	}
+	if reflect.PtrTo(f.Type()).Implements(textUnmarshalerType) {
+		if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid value of field: "+path+". "+err.Error())
+		}
+		return
+	}
+	if f.Type() == durationType {
+		if d, err := time.ParseDuration(s); err != nil {
+			vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid value of field: "+path+". "+err.Error())
+		} else {
+			f.SetInt(int64(d))
+		}
+		return
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() == reflect.Uint8 {
+			f.SetBytes([]byte(s))
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err != nil {
+			vl.typeError(path, "Boolean")
+		} else {
+			f.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(s, 10, f.Type().Bits()); err != nil {
+			vl.numberError(path, f.Type(), err)
+		} else {
+			f.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(s, 10, f.Type().Bits()); err != nil {
+			vl.numberError(path, f.Type(), err)
+		} else {
+			f.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(s, f.Type().Bits()); err != nil {
+			vl.numberError(path, f.Type(), err)
+		} else {
+			f.SetFloat(n)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package v
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"github.com/dimonrus/porterr"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of json interfaces
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// jsonField field of struct as encoding/json decodes it
+type jsonField struct {
+	// Type of field
+	t reflect.Type
+	// Value is encoded in json string with ",string" option
+	quoted bool
+}
+
+// DecodeJSON decode json into struct and validate it
+// Decode errors, type mismatches and validation errors are reported in one validation error with field paths
+// Example: Invalid type of field: items[2].qty. Number required
+func DecodeJSON(r io.Reader, dst interface{}) porterr.IError {
+	return decodeJSON(r, dst, false)
+}
+
+// DecodeStrictJSON decode json into struct and validate it as DecodeJSON does
+// Fields which are not present in struct are reported as errors
+func DecodeStrictJSON(r io.Reader, dst interface{}) porterr.IError {
+	return decodeJSON(r, dst, true)
+}
+
+// decodeJSON decode json into struct, check types of values and validate the struct
+func decodeJSON(r io.Reader, dst interface{}, strict bool) porterr.IError {
+	ve, e := structPointer(dst)
+	if e != nil {
+		return e
+	}
+	vl := newValidator()
+	if e = vl.decodeJSON(r, ve.Elem(), strict); e != nil {
+		return e
+	}
+	return vl.validateDecoded(ve.Elem())
+}
+
+// structPointer check if destination of decoding is pointer to struct
+func structPointer(dst interface{}) (reflect.Value, porterr.IError) {
+	ve := reflect.ValueOf(dst)
+	if ve.Kind() != reflect.Ptr || ve.IsNil() || ve.Elem().Kind() != reflect.Struct {
+		return ve, porterr.New(porterr.PortErrorArgument, "Pointer to struct required")
+	}
+	return ve, nil
+}
+
+// decodeJSON decode json into struct and push error details of values which could not be decoded
+// Returns error if json is malformed or is not an object
+func (vl *validator) decodeJSON(r io.Reader, ve reflect.Value, strict bool) porterr.IError {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return bodyError("Invalid body: ", err)
+	}
+	e := porterr.HttpValidationError()
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&document); err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	if err != nil {
+		return e.PushDetail(porterr.PortErrorParam, "body", "Invalid json: "+err.Error())
+	}
+	if _, ok := document.(map[string]interface{}); !ok {
+		return e.PushDetail(porterr.PortErrorParam, "type", "Type object required. Type "+jsonKind(document)+" received")
+	}
+	vl.checkJSON(document, ve.Type(), "", false, strict)
+	if err = json.Unmarshal(data, ve.Addr().Interface()); err != nil && !vl.isDecodeErrorReported(err) {
+		vl.e.PushDetail(porterr.PortErrorParam, "body", "Invalid json: "+err.Error())
+	}
+	return nil
+}
+
+// validateDecoded validate decoded struct and merge errors with decode errors
+// Fields with decode errors are not validated again
+func (vl *validator) validateDecoded(ve reflect.Value) porterr.IError {
+	decoded := make(map[string]struct{})
+	for _, detail := range vl.e.GetDetails() {
+		decoded[detail.Origin().Name] = struct{}{}
+	}
+	sv := newValidator()
+	if ce := sv.validateStruct(ve, reflect.Value{}, ""); ce != nil {
+		return ce
+	}
+	for _, detail := range sv.e.GetDetails() {
+		if _, ok := decoded[detail.Origin().Name]; !ok {
+			vl.e.PushDetail(detail.GetCode(), detail.Origin().Name, detail.Error())
+		}
+	}
+	return vl.e.IfDetails()
+}
+
+// checkJSON check if decoded json value could be decoded into the type and push error details
+// Values of types implementing json.Unmarshaler are checked by decoding
+func (vl *validator) checkJSON(value interface{}, t reflect.Type, path string, quoted bool, strict bool) {
+	if value == nil {
+		return
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		vl.checkUnmarshaler(value, t, path)
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		if _, ok := value.(string); !ok {
+			vl.typeError(path, "String")
+			return
+		}
+		vl.checkUnmarshaler(value, t, path)
+		return
+	}
+	if s, ok := value.(string); ok && quoted {
+		var unquoted interface{}
+		decoder := json.NewDecoder(strings.NewReader(s))
+		decoder.UseNumber()
+		if decoder.Decode(&unquoted) != nil {
+			vl.typeError(path, "Quoted value")
+			return
+		}
+		value = unquoted
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			vl.typeError(path, "Object")
+			return
+		}
+		fields := jsonFields(t, nil)
+		for _, key := range sortedJSONKeys(object) {
+			field, ok := fields[key]
+			if !ok {
+				for name := range fields {
+					if strings.EqualFold(name, key) {
+						field, ok = fields[name], true
+						break
+					}
+				}
+			}
+			if !ok {
+				if strict {
+					vl.e.PushDetail(porterr.PortErrorParam, fieldPath(path, key), "Unknown field: "+fieldPath(path, key))
+				}
+				continue
+			}
+			vl.checkJSON(object[key], field.t, fieldPath(path, key), field.quoted, strict)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			vl.typeError(path, "Object")
+			return
+		}
+		for _, key := range sortedJSONKeys(object) {
+			vl.checkJSON(object[key], t.Elem(), mapKeyName(path, reflect.ValueOf(key)), false, strict)
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := value.(string); !ok {
+				vl.typeError(path, "String")
+			}
+			return
+		}
+		array, ok := value.([]interface{})
+		if !ok {
+			vl.typeError(path, "Array")
+			return
+		}
+		for i := range array {
+			vl.checkJSON(array[i], t.Elem(), indexName(path, i), false, strict)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			vl.typeError(path, "String")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			vl.typeError(path, "Boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := value.(json.Number); !ok {
+			vl.typeError(path, "Number")
+		} else if _, err := strconv.ParseInt(string(n), 10, t.Bits()); err != nil {
+			vl.numberError(path, t, err)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := value.(json.Number); !ok {
+			vl.typeError(path, "Number")
+		} else if _, err := strconv.ParseUint(string(n), 10, t.Bits()); err != nil {
+			vl.numberError(path, t, err)
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := value.(json.Number); !ok {
+			vl.typeError(path, "Number")
+		} else if _, err := strconv.ParseFloat(string(n), t.Bits()); err != nil {
+			vl.numberError(path, t, err)
+		}
+	}
+}
+
+// checkUnmarshaler check if value could be decoded by type implementing json.Unmarshaler
+func (vl *validator) checkUnmarshaler(value interface{}, t reflect.Type, path string) {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, reflect.New(t).Interface())
+	}
+	if err != nil {
+		vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid value of field: "+path+". "+err.Error())
+	}
+}
+
+// typeError push type mismatch error
+func (vl *validator) typeError(path string, expected string) {
+	vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid type of field: "+path+". "+expected+" required")
+}
+
+// numberError push error of number which could not be decoded into the type
+func (vl *validator) numberError(path string, t reflect.Type, err error) {
+	if err.(*strconv.NumError).Err == strconv.ErrRange {
+		vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid value of field: "+path+". Number is out of range of type "+t.String())
+		return
+	}
+	vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid value of field: "+path+". Number of type "+t.String()+" required")
+}
+
+// jsonFields fields of struct by json names as encoding/json decodes them
+// Fields of embedded structs are promoted, fields of outer struct take precedence
+func jsonFields(t reflect.Type, fields map[string]jsonField) map[string]jsonField {
+	if fields == nil {
+		fields = make(map[string]jsonField)
+	}
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, promote := fieldName(field)
+		if promote {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			embedded = append(embedded, ft)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = jsonField{t: field.Type, quoted: strings.Contains(tag, ",string")}
+		}
+	}
+	for _, et := range embedded {
+		jsonFields(et, fields)
+	}
+	return fields
+}
+
+// jsonKind name of decoded json value type
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	}
+	return "object"
+}
+
+// sortedJSONKeys keys of json object in stable order
+func sortedJSONKeys(object map[string]interface{}) []string {
+	var keys = make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isDecodeErrorReported check if error of json.Unmarshal is already reported by checkJSON
+// Type errors are matched by field path, other errors by message
+func (vl *validator) isDecodeErrorReported(err error) bool {
+	typeError, ok := err.(*json.UnmarshalTypeError)
+	for _, detail := range vl.e.GetDetails() {
+		if ok {
+			indexed, stripped := unmarshalPaths(detail.Origin().Name)
+			if typeError.Field == indexed || typeError.Field == stripped {
+				return true
+			}
+		} else if strings.HasSuffix(detail.Error(), err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalPaths field path as encoding/json reports it with and without indexes
+// Example: items[2].qty -> items.2.qty, items.qty
+func unmarshalPaths(path string) (string, string) {
+	var indexed, stripped strings.Builder
+	var depth int
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '[':
+			if depth == 0 {
+				indexed.WriteByte('.')
+			}
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			indexed.WriteByte(c)
+			stripped.WriteByte(c)
+		case c != '"':
+			indexed.WriteByte(c)
+		}
+	}
+	return indexed.String(), stripped.String()
+}
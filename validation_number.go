@@ -0,0 +1,294 @@
+package v
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseDecimal parse decimal number exactly
+// Example: 10, -0.01, 1.5e3
+func ParseDecimal(s string) (*big.Rat, bool) {
+	if s == "" || strings.ContainsAny(s, "/_") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// numberValue exact value of number or numeric string
+// Floats are taken in the shortest decimal representation
+// Returns false if value is not a number, NaN or Inf
+func numberValue(val reflect.Value) (*big.Rat, bool) {
+	if r, ok := bigNumber(val); ok {
+		return r, r != nil
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return ParseDecimal(strings.TrimSpace(val.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(val.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return ParseDecimal(strconv.FormatFloat(f, 'f', -1, val.Type().Bits()))
+	}
+	return nil, false
+}
+
+// isNumber check if value is a number, big number or numeric string
+func isNumber(val reflect.Value) bool {
+	if isBigType(val.Type()) {
+		return true
+	}
+	switch val.Kind() {
+	case reflect.String,
+		reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isNumberValid check number with function
+// Nil pointers and empty strings are valid, items of slice are checked separately
+func isNumberValid(val reflect.Value, check func(val reflect.Value) bool) bool {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return true
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if !isNumberValid(val.Index(i), check) {
+				return false
+			}
+		}
+		return true
+	case reflect.String:
+		if val.Len() == 0 {
+			return true
+		}
+	}
+	if !isNumber(val) {
+		return true
+	}
+	return check(val)
+}
+
+// numberSign sign of number. Infinities have sign, NaN is not valid
+func numberSign(val reflect.Value) (int, bool) {
+	if val.Type() == bigFloatType && val.CanInterface() {
+		f := val.Interface().(big.Float)
+		return f.Sign(), true
+	}
+	if val.Kind() == reflect.Float32 || val.Kind() == reflect.Float64 {
+		f := val.Float()
+		switch {
+		case math.IsNaN(f):
+			return 0, false
+		case f > 0:
+			return 1, true
+		case f < 0:
+			return -1, true
+		}
+		return 0, true
+	}
+	r, ok := numberValue(val)
+	if !ok {
+		return 0, false
+	}
+	return r.Sign(), true
+}
+
+// decimalDigits count of integer digits and decimal places of number
+func decimalDigits(r *big.Rat) (int, int, bool) {
+	// shift number until it is integer
+	var scale int
+	n := new(big.Rat).Abs(r)
+	ten := big.NewRat(10, 1)
+	for !n.IsInt() {
+		// denominator must contain only factors 2 and 5
+		if scale > 1000 {
+			return 0, 0, false
+		}
+		n.Mul(n, ten)
+		scale++
+	}
+	digits := len(n.Num().String())
+	if n.Sign() == 0 {
+		digits = 0
+	}
+	return digits - scale, scale, true
+}
+
+// IsPositiveValid check if number is greater than zero
+func IsPositiveValid(val reflect.Value, args ...string) bool {
+	return isNumberValid(val, func(val reflect.Value) bool {
+		sign, ok := numberSign(val)
+		return ok && sign > 0
+	})
+}
+
+// IsNegativeValid check if number is less than zero
+func IsNegativeValid(val reflect.Value, args ...string) bool {
+	return isNumberValid(val, func(val reflect.Value) bool {
+		sign, ok := numberSign(val)
+		return ok && sign < 0
+	})
+}
+
+// IsNonZeroValid check if number is not zero
+func IsNonZeroValid(val reflect.Value, args ...string) bool {
+	return isNumberValid(val, func(val reflect.Value) bool {
+		sign, ok := numberSign(val)
+		return ok && sign != 0
+	})
+}
+
+// IsFiniteValid check if number is not NaN or Inf. Numeric string must be a valid number
+func IsFiniteValid(val reflect.Value, args ...string) bool {
+	return isNumberValid(val, func(val reflect.Value) bool {
+		_, ok := numberValue(val)
+		return ok
+	})
+}
+
+// IsMultipleOfValid check if number is multiple of argument
+// Example: multipleOf~0.01
+func IsMultipleOfValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	m, ok := ParseDecimal(args[0])
+	if !ok || m.Sign() == 0 {
+		return false
+	}
+	return isNumberValid(val, func(val reflect.Value) bool {
+		r, ok := numberValue(val)
+		return ok && r.Quo(r, m).IsInt()
+	})
+}
+
+// IsScaleValid check maximum count of decimal places
+// Example: scale~2
+func IsScaleValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	max, err := strconv.Atoi(args[0])
+	if err != nil {
+		return false
+	}
+	return isNumberValid(val, func(val reflect.Value) bool {
+		r, ok := numberValue(val)
+		if !ok {
+			return false
+		}
+		_, scale, ok := decimalDigits(r)
+		return ok && scale <= max
+	})
+}
+
+// IsPrecisionValid check maximum count of digits and decimal places as sql numeric(precision,scale)
+// Example: precision~10,2 allows 8 integer digits and 2 decimal places
+func IsPrecisionValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	precision, maxScale, ok := parsePrecision(args[0])
+	if !ok {
+		return false
+	}
+	return isNumberValid(val, func(val reflect.Value) bool {
+		r, ok := numberValue(val)
+		if !ok {
+			return false
+		}
+		digits, scale, ok := decimalDigits(r)
+		return ok && scale <= maxScale && digits <= precision-maxScale
+	})
+}
+
+// parsePrecision parse precision and optional scale
+func parsePrecision(arg string) (int, int, bool) {
+	var scale int
+	var err error
+	if i := strings.IndexByte(arg, ','); i >= 0 {
+		scale, err = strconv.Atoi(arg[i+1:])
+		if err != nil {
+			return 0, 0, false
+		}
+		arg = arg[:i]
+	}
+	precision, err := strconv.Atoi(arg)
+	if err != nil || precision <= 0 || scale < 0 || scale > precision {
+		return 0, 0, false
+	}
+	return precision, scale, true
+}
+
+// NumericStringer decimal-like type that is compared as exact number by numeric rules
+type NumericStringer interface {
+	// NumericString number in decimal notation. Example: -10.25
+	NumericString() string
+}
+
+// Types of big numbers
+var (
+	bigIntType          = reflect.TypeOf(big.Int{})
+	bigFloatType        = reflect.TypeOf(big.Float{})
+	bigRatType          = reflect.TypeOf(big.Rat{})
+	numericStringerType = reflect.TypeOf((*NumericStringer)(nil)).Elem()
+)
+
+// isBigType check if type is big number or implements NumericStringer
+func isBigType(t reflect.Type) bool {
+	switch t {
+	case bigIntType, bigFloatType, bigRatType:
+		return true
+	}
+	return t.Implements(numericStringerType) || reflect.PtrTo(t).Implements(numericStringerType)
+}
+
+// bigNumber exact value of big numbers and NumericStringer types
+// Returns false if value is not such type and nil if value is not a valid number
+func bigNumber(val reflect.Value) (*big.Rat, bool) {
+	if !isBigType(val.Type()) {
+		return nil, false
+	}
+	if !val.CanInterface() {
+		return nil, true
+	}
+	if val.CanAddr() {
+		val = val.Addr()
+	} else {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		val = ptr
+	}
+	switch v := val.Interface().(type) {
+	case NumericStringer:
+		r, _ := ParseDecimal(v.NumericString())
+		return r, true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), true
+	case *big.Rat:
+		return new(big.Rat).Set(v), true
+	case *big.Float:
+		if v.IsInf() {
+			return nil, true
+		}
+		r, _ := ParseDecimal(v.Text('g', -1))
+		return r, true
+	}
+	return nil, true
+}
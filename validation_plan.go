@@ -0,0 +1,289 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// fieldPlan compiled validation of struct field
+type fieldPlan struct {
+	// Field index in struct
+	index int
+	// Field name used in errors
+	name string
+	// Compiled rules
+	rules valuePlan
+	// Embedded struct which fields are promoted
+	embedded bool
+	// Unexported field
+	unexported bool
+	// Union of variants selected by discriminator field
+	union *unionPlan
+}
+
+// valuePlan compiled rules of value and its elements
+type valuePlan struct {
+	// Rules of the value
+	rules ValidationRules
+	// Rules of map keys
+	keys ValidationRules
+	// Rules comparing value with old one
+	update ValidationRules
+	// Plan of collection elements or map values
+	dive *valuePlan
+}
+
+// structPlan compiled validation of struct type
+type structPlan struct {
+	// Fields to validate
+	fields []fieldPlan
+	// Group constraints of fields
+	groups []groupPlan
+	// Compile error
+	e porterr.IError
+}
+
+// Compiled plans. reflect.Type -> *structPlan
+var plans sync.Map
+
+// CompileStruct compile validation plan for struct and report tag errors
+// Use it at start of application to check tags of the struct
+func CompileStruct(v interface{}) porterr.IError {
+	te := reflect.TypeOf(v)
+	for te != nil && te.Kind() == reflect.Ptr {
+		te = te.Elem()
+	}
+	if te == nil || te.Kind() != reflect.Struct {
+		return porterr.New(porterr.PortErrorArgument, "Type struct required")
+	}
+	return getStructPlan(te).e
+}
+
+// resetPlans remove all compiled plans
+// Must be called when rules or aliases are changed
+func resetPlans() {
+	plans.Range(func(key, value interface{}) bool {
+		plans.Delete(key)
+		return true
+	})
+	varPlans.Range(func(key, value interface{}) bool {
+		if _, ok := varPlans.LoadAndDelete(key); ok {
+			atomic.AddInt64(&varPlansCount, -1)
+		}
+		return true
+	})
+}
+
+// getStructPlan get compiled plan for struct type
+func getStructPlan(te reflect.Type) *structPlan {
+	if plan, ok := plans.Load(te); ok {
+		return plan.(*structPlan)
+	}
+	plan, _ := plans.LoadOrStore(te, compileStructPlan(te))
+	return plan.(*structPlan)
+}
+
+// compileStructPlan compile validation plan for struct type
+func compileStructPlan(te reflect.Type) *structPlan {
+	plan := &structPlan{fields: make([]fieldPlan, 0, te.NumField())}
+	for i := 0; i < te.NumField(); i++ {
+		t := te.Field(i)
+		validTag := t.Tag.Get("valid")
+		if validTag == "-" {
+			continue
+		}
+		field := fieldPlan{index: i}
+		field.name, field.embedded = fieldName(t)
+		field.unexported = t.PkgPath != "" && !field.embedded
+		if t.Anonymous && t.PkgPath != "" && t.Type.Kind() == reflect.Ptr {
+			// encoding/json ignores unexported embedded pointers
+			continue
+		}
+		rules, e := compileRules(validTag, schemaRules(te, i)...)
+		if e == nil {
+			rules, e = plan.addGroups(rules, i, field.name)
+		}
+		if e == nil {
+			rules, field.union, e = compileUnion(rules, te)
+		}
+		if e == nil {
+			field.rules, e = compileValuePlan(rules, t.Type)
+		}
+		if e != nil {
+			plan.e = porterr.New(porterr.PortErrorParser, "Field "+te.String()+"."+t.Name+". "+e.Error())
+			return plan
+		}
+		plan.fields = append(plan.fields, field)
+	}
+	if e := plan.addRegisteredGroups(te); e != nil {
+		plan.e = porterr.New(porterr.PortErrorParser, "Struct "+te.String()+". "+e.Error())
+	}
+	return plan
+}
+
+// fieldName name of the field as encoding/json produces it
+// Returns true when fields of embedded struct must be promoted
+func fieldName(t reflect.StructField) (string, bool) {
+	name := t.Tag.Get("json")
+	if i := strings.IndexByte(name, ','); i >= 0 {
+		name = name[:i]
+	}
+	if name == "-" && t.Tag.Get("json") == "-" {
+		name = ""
+	}
+	if name != "" {
+		return name, false
+	}
+	ft := t.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	return t.Name, t.Anonymous && ft.Kind() == reflect.Struct
+}
+
+// compileRules parse tag and expand aliases
+// Rules defined in code are appended to rules of tag
+func compileRules(validTag string, defined ...ValidationRule) (ValidationRules, porterr.IError) {
+	rules, e := parseValidTag(validTag)
+	if e != nil {
+		return nil, e
+	}
+	return expandAliases(append(rules, defined...), nil)
+}
+
+// compileValuePlan split rules by dive markers
+// Rules before dive are applied to the value, after "dive~keys" to map keys
+// and after "dive" to collection elements or map values
+func compileValuePlan(rules ValidationRules, t reflect.Type) (valuePlan, porterr.IError) {
+	var plan valuePlan
+	var keys bool
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i, rule := range rules {
+		if rule.Name != ruleDive {
+			if containsDive(rule.Alternatives) {
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+ruleDive+" is not allowed in alternatives and groups")
+			}
+			if name, ok := containsUpdateRule(rule.Alternatives); ok {
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+name+" is not allowed in alternatives, groups and aliases")
+			}
+			if name, ok := unknownRule(rule, false); ok {
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+name+" is not registered. Unknown rules are not allowed in alternatives and negations")
+			}
+			if _, ok := actualUpdateRules[rule.Name]; ok {
+				if rule.Not {
+					return plan, porterr.New(porterr.PortErrorParser, "Rule "+rule.Name+" could not be negated")
+				}
+				if keys {
+					return plan, porterr.New(porterr.PortErrorParser, "Rule "+rule.Name+" is not applicable to map keys")
+				}
+				if e := checkRulesArgs(ValidationRules{rule}, t); e != nil {
+					return plan, e
+				}
+				plan.update = append(plan.update, rule)
+				continue
+			}
+			if keys {
+				keyType := t
+				if t.Kind() == reflect.Map {
+					keyType = t.Key()
+				}
+				if e := checkRulesArgs(ValidationRules{rule}, keyType); e != nil {
+					return plan, e
+				}
+				plan.keys = append(plan.keys, rule)
+			} else {
+				if e := checkRulesArgs(ValidationRules{rule}, t); e != nil {
+					return plan, e
+				}
+				plan.rules = append(plan.rules, rule)
+			}
+			continue
+		}
+		if rule.Not {
+			return plan, porterr.New(porterr.PortErrorParser, "Rule "+ruleDive+" could not be negated")
+		}
+		var target string
+		if len(rule.Args) > 0 {
+			target = rule.Args[0]
+		}
+		switch target {
+		case diveKeys:
+			if t.Kind() != reflect.Map && t.Kind() != reflect.Interface {
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+rule.String()+" is not applicable to type "+t.String())
+			}
+			if keys || len(plan.keys) > 0 {
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+rule.String()+" is duplicated")
+			}
+			keys = true
+		case "", diveValues:
+			var elem = reflect.TypeOf((*interface{})(nil)).Elem()
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				elem = t.Elem()
+			case reflect.Interface:
+			default:
+				return plan, porterr.New(porterr.PortErrorParser, "Rule "+ruleDive+" is not applicable to type "+t.String())
+			}
+			dive, e := compileValuePlan(rules[i+1:], elem)
+			if e != nil {
+				return plan, e
+			}
+			plan.dive = &dive
+			return plan, nil
+		default:
+			return plan, porterr.New(porterr.PortErrorParser, "Unknown "+ruleDive+" target "+target)
+		}
+	}
+	return plan, nil
+}
+
+// unknownRule find rule which is not registered inside alternatives or negation
+// Such rule would make alternative always valid or negation always invalid, so it is reported on compile
+func unknownRule(rule ValidationRule, nested bool) (string, bool) {
+	if len(rule.Alternatives) > 0 {
+		for _, alternative := range rule.Alternatives {
+			for _, r := range alternative {
+				if name, ok := unknownRule(r, nested || len(rule.Alternatives) > 1 || rule.Not); ok {
+					return name, true
+				}
+			}
+		}
+		return "", false
+	}
+	if _, ok := actualValidationRules[rule.Name]; !ok && (nested || rule.Not) {
+		return rule.Name, true
+	}
+	return "", false
+}
+
+// containsUpdateRule check if update rule is used inside alternatives
+func containsUpdateRule(alternatives []ValidationRules) (string, bool) {
+	for _, alternative := range alternatives {
+		for _, rule := range alternative {
+			if _, ok := actualUpdateRules[rule.Name]; ok {
+				return rule.Name, true
+			}
+			if name, ok := containsUpdateRule(rule.Alternatives); ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containsDive check if dive is used inside alternatives
+func containsDive(alternatives []ValidationRules) bool {
+	for _, alternative := range alternatives {
+		for _, rule := range alternative {
+			if rule.Name == ruleDive || containsDive(rule.Alternatives) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package v
+
+import (
+	"reflect"
+	"strings"
+)
+
+// interval range interval. Empty bound means unbounded side
+type interval struct {
+	// Lower bound
+	left string
+	// Upper bound
+	right string
+	// Lower bound is excluded
+	leftOpen bool
+	// Upper bound is excluded
+	rightOpen bool
+}
+
+// parseRange parse range argument into list of intervals
+// Example: 1:5,10:20 or [0,1),(5,]
+func parseRange(arg string) ([]interval, bool) {
+	var intervals []interval
+	var start, depth int
+	for i := 0; i <= len(arg); i++ {
+		if i < len(arg) {
+			switch arg[i] {
+			case '[', '(':
+				depth++
+				continue
+			case ']', ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		item, ok := parseInterval(arg[start:i])
+		if !ok {
+			return nil, false
+		}
+		intervals = append(intervals, item)
+		start = i + 1
+	}
+	return intervals, len(intervals) > 0
+}
+
+// parseInterval parse single interval in a:b or [a,b) notation
+func parseInterval(s string) (interval, bool) {
+	var item interval
+	if s == "" {
+		return item, false
+	}
+	if s[0] == '[' || s[0] == '(' {
+		last := s[len(s)-1]
+		if len(s) < 3 || (last != ']' && last != ')') {
+			return item, false
+		}
+		bounds := strings.Split(s[1:len(s)-1], ",")
+		if len(bounds) != 2 {
+			return item, false
+		}
+		item.left, item.right = bounds[0], bounds[1]
+		item.leftOpen, item.rightOpen = s[0] == '(', last == ')'
+		return item, true
+	}
+	delim := strings.IndexByte(s, ':')
+	if delim < 0 {
+		return item, false
+	}
+	item.left, item.right = s[:delim], s[delim+1:]
+	return item, true
+}
+
+// contains check if value is in the interval
+func (i interval) contains(val reflect.Value) bool {
+	if i.left != "" {
+		cmp, ok := compareRangeBound(val, i.left)
+		if !ok || cmp < 0 || (cmp == 0 && i.leftOpen) {
+			return false
+		}
+	}
+	if i.right != "" {
+		cmp, ok := compareRangeBound(val, i.right)
+		if !ok || cmp > 0 || (cmp == 0 && i.rightOpen) {
+			return false
+		}
+	}
+	return true
+}
+
+// bounds list of interval bounds
+func (i interval) bounds() []string {
+	var bounds []string
+	if i.left != "" {
+		bounds = append(bounds, i.left)
+	}
+	if i.right != "" {
+		bounds = append(bounds, i.right)
+	}
+	return bounds
+}
+
+// compareRangeBound compare value with the range bound
+// Big numbers are compared exactly, strings lexicographically, times are compared with parsed time
+func compareRangeBound(val reflect.Value, bound string) (int, bool) {
+	if r, ok := bigNumber(val); ok {
+		b, ok := ParseDecimal(bound)
+		if r == nil || !ok {
+			return 0, false
+		}
+		return r.Cmp(b), true
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return strings.Compare(val.String(), bound), true
+	case reflect.Struct:
+		t, ok := timeValue(val)
+		if !ok {
+			return 0, false
+		}
+		b, err := ParseTime(bound, Now())
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case t.Before(b):
+			return -1, true
+		case t.After(b):
+			return 1, true
+		}
+		return 0, true
+	}
+	return compareBound(val, bound)
+}
@@ -30,6 +30,7 @@ func IsRegularValid(val reflect.Value, args ...string) bool {
 		}
 		val = val.Elem()
 	}
+	val = textValue(val)
 	if val.Kind() != reflect.String {
 		return false
 	}
@@ -43,8 +44,20 @@ func IsRegularValid(val reflect.Value, args ...string) bool {
 }
 
 // IsEnumValid In list validation rule
+// Values are listed in argument, referenced as registered enum or taken from Enumer type
+// Example: enum~new,paid, enum~@OrderStatus
 func IsEnumValid(val reflect.Value, args ...string) bool {
-	if len(args) == 0 || val.IsZero() {
+	return isEnumValid(val, args, false)
+}
+
+// IsEnumFoldValid case-insensitive In list validation rule
+func IsEnumFoldValid(val reflect.Value, args ...string) bool {
+	return isEnumValid(val, args, true)
+}
+
+// isEnumValid check if value is in list. Strings are compared case-insensitive if fold is set
+func isEnumValid(val reflect.Value, args []string, fold bool) bool {
+	if val.IsZero() {
 		return true
 	}
 	if val.Kind() == reflect.Ptr {
@@ -53,15 +66,39 @@ func IsEnumValid(val reflect.Value, args ...string) bool {
 		}
 		val = val.Elem()
 	}
-	values := strings.Split(args[0], ",")
+	text := textValue(val)
+	if text.Kind() == reflect.Slice {
+		for i := 0; i < text.Len(); i++ {
+			if !isEnumValid(text.Index(i), args, fold) {
+				return false
+			}
+		}
+		return true
+	}
+	values, ok := enumValues(val.Type(), args)
+	if !ok {
+		return len(args) == 0
+	}
+	val = text
 	if len(values) == 0 {
 		return true
 	}
+	if r, ok := bigNumber(val); ok {
+		if r == nil {
+			return false
+		}
+		for _, value := range values {
+			if comp, ok := ParseDecimal(value); ok && r.Cmp(comp) == 0 {
+				return true
+			}
+		}
+		return false
+	}
 	switch val.Kind() {
 	case reflect.String:
 		v := val.String()
 		for _, value := range values {
-			if v == value {
+			if v == value || (fold && strings.EqualFold(v, value)) {
 				return true
 			}
 		}
@@ -120,17 +157,13 @@ func IsEnumValid(val reflect.Value, args ...string) bool {
 			}
 		}
 		return false
-	case reflect.Slice:
-		for i := 0; i < val.Len(); i++ {
-			if !IsEnumValid(val.Index(i), args...) {
-				return false
-			}
-		}
 	}
 	return true
 }
 
 // IsRangeValid Range list validation rule
+// Supports intervals a:b, :b, a:, [a,b), (a,] and unions of intervals separated by comma: 1:5,10:20
+// Strings are compared lexicographically, times and durations are supported
 func IsRangeValid(val reflect.Value, args ...string) bool {
 	if len(args) == 0 {
 		return true
@@ -141,85 +174,65 @@ func IsRangeValid(val reflect.Value, args ...string) bool {
 		}
 		val = val.Elem()
 	}
-	delim := strings.Index(args[0], ":")
-	if delim < 0 {
-		return false
-	}
-	left := args[0][:delim]
-	right := args[0][delim+1:]
 	switch val.Kind() {
-	case reflect.Float64:
-		fallthrough
-	case reflect.Float32:
-		v := val.Float()
-		min, err := strconv.ParseFloat(left, 64)
-		if err != nil {
-			return false
-		}
-		max, err := strconv.ParseFloat(right, 64)
-		if err != nil {
-			return false
-		}
-		if v >= min && max >= v {
-			return true
-		}
-		return false
-	case reflect.Int:
-		fallthrough
-	case reflect.Int8:
-		fallthrough
-	case reflect.Int16:
-		fallthrough
-	case reflect.Int32:
-		fallthrough
-	case reflect.Int64:
-		v := val.Int()
-		min, err := strconv.ParseInt(left, 10, 64)
-		if err != nil {
-			return false
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if !IsRangeValid(val.Index(i), args...) {
+				return false
+			}
 		}
-		max, err := strconv.ParseInt(right, 10, 64)
-		if err != nil {
-			return false
+		return true
+	case reflect.Struct:
+		if isBigType(val.Type()) {
+			break
 		}
-		if v >= min && v <= max {
+		if t, ok := timeValue(val); !ok || t.IsZero() {
 			return true
 		}
+	case reflect.String,
+		reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return true
+	}
+	intervals, ok := parseRange(args[0])
+	if !ok {
 		return false
-	case reflect.Uint:
-		fallthrough
-	case reflect.Uint8:
-		fallthrough
-	case reflect.Uint16:
-		fallthrough
-	case reflect.Uint32:
-		fallthrough
-	case reflect.Uint64:
-		v := val.Uint()
-		min, err := strconv.ParseUint(left, 10, 64)
-		if err != nil {
-			return false
-		}
-		max, err := strconv.ParseUint(right, 10, 64)
-		if err != nil {
-			return false
-		}
-		if v >= min && v <= max {
+	}
+	for _, i := range intervals {
+		if i.contains(val) {
 			return true
 		}
-		return false
-	case reflect.Slice:
-		for i := 0; i < val.Len(); i++ {
-			if !IsRangeValid(val.Index(i), args...) {
-				return false
-			}
-		}
 	}
-	return true
+	return false
 }
 
 // IsMinValid check min
+// Value must be greater or equal to the bound. Length is checked for strings
 func IsMinValid(val reflect.Value, args ...string) bool {
+	return isBoundValid(val, args, func(cmp int) bool { return cmp >= 0 })
+}
+
+// IsMaxValid check max
+// Value must be less or equal to the bound. Length is checked for strings
+func IsMaxValid(val reflect.Value, args ...string) bool {
+	return isBoundValid(val, args, func(cmp int) bool { return cmp <= 0 })
+}
+
+// IsGtValid check if value is greater than the bound
+func IsGtValid(val reflect.Value, args ...string) bool {
+	return isBoundValid(val, args, func(cmp int) bool { return cmp > 0 })
+}
+
+// IsLtValid check if value is less than the bound
+func IsLtValid(val reflect.Value, args ...string) bool {
+	return isBoundValid(val, args, func(cmp int) bool { return cmp < 0 })
+}
+
+// isBoundValid compare value with the bound and check result of comparison
+// Items of slice are checked separately
+func isBoundValid(val reflect.Value, args []string, check func(cmp int) bool) bool {
 	if len(args) == 0 {
 		return true
 	}
@@ -229,40 +242,21 @@ func IsMinValid(val reflect.Value, args ...string) bool {
 		}
 		val = val.Elem()
 	}
-	min, err := strconv.Atoi(args[0])
-	if err != nil {
-		return false
+	val = textValue(val)
+	if r, ok := bigNumber(val); ok {
+		b, ok := ParseDecimal(args[0])
+		return r != nil && ok && check(r.Cmp(b))
 	}
 	switch val.Kind() {
-	case reflect.String:
-		return len([]rune(val.String())) >= min
-	case reflect.Float64:
-		fallthrough
-	case reflect.Float32:
-		return val.Float() >= float64(min)
-	case reflect.Int:
-		fallthrough
-	case reflect.Int8:
-		fallthrough
-	case reflect.Int16:
-		fallthrough
-	case reflect.Int32:
-		fallthrough
-	case reflect.Int64:
-		return val.Int() >= int64(min)
-	case reflect.Uint:
-		fallthrough
-	case reflect.Uint8:
-		fallthrough
-	case reflect.Uint16:
-		fallthrough
-	case reflect.Uint32:
-		fallthrough
-	case reflect.Uint64:
-		return val.Uint() >= uint64(min)
-	case reflect.Slice:
+	case reflect.String,
+		reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		cmp, ok := compareBound(val, args[0])
+		return ok && check(cmp)
+	case reflect.Slice, reflect.Array:
 		for i := 0; i < val.Len(); i++ {
-			if !IsMinValid(val.Index(i), args...) {
+			if !isBoundValid(val.Index(i), args, check) {
 				return false
 			}
 		}
@@ -270,56 +264,69 @@ func IsMinValid(val reflect.Value, args ...string) bool {
 	return true
 }
 
-// IsMaxValid check max
-func IsMaxValid(val reflect.Value, args ...string) bool {
-	if len(args) == 0 {
-		return true
-	}
-	if val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			return true
+// compareBound compare value with the bound parsed according to kind of value
+// Returns -1, 0 or 1 and false if bound could not be parsed or value is NaN
+func compareBound(val reflect.Value, bound string) (int, bool) {
+	if val.Type() == durationType {
+		d, err := ParseDuration(bound)
+		if err != nil {
+			return 0, false
 		}
-		val = val.Elem()
-	}
-	max, err := strconv.Atoi(args[0])
-	if err != nil {
-		return false
+		return compareInt(val.Int(), int64(d)), true
 	}
 	switch val.Kind() {
 	case reflect.String:
-		return len([]rune(val.String())) <= max
-	case reflect.Float64:
-		fallthrough
-	case reflect.Float32:
-		return val.Float() <= float64(max)
-	case reflect.Int:
-		fallthrough
-	case reflect.Int8:
-		fallthrough
-	case reflect.Int16:
-		fallthrough
-	case reflect.Int32:
-		fallthrough
-	case reflect.Int64:
-		return val.Int() <= int64(max)
-	case reflect.Uint:
-		fallthrough
-	case reflect.Uint8:
-		fallthrough
-	case reflect.Uint16:
-		fallthrough
-	case reflect.Uint32:
-		fallthrough
-	case reflect.Uint64:
-		return val.Uint() <= uint64(max)
-	case reflect.Slice:
-		for i := 0; i < val.Len(); i++ {
-			if !IsMaxValid(val.Index(i), args...) {
-				return false
+		l, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, false
+		}
+		return compareInt(int64(len([]rune(val.String()))), int64(l)), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if b, err := strconv.ParseInt(bound, 10, 64); err == nil {
+			return compareInt(val.Int(), b), true
+		}
+		return compareFloat(float64(val.Int()), bound)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if b, err := strconv.ParseUint(bound, 10, 64); err == nil {
+			switch {
+			case val.Uint() < b:
+				return -1, true
+			case val.Uint() > b:
+				return 1, true
 			}
+			return 0, true
 		}
+		return compareFloat(float64(val.Uint()), bound)
+	case reflect.Float32, reflect.Float64:
+		return compareFloat(val.Float(), bound)
 	}
-	return true
+	return 0, false
+}
+
+// compareInt compare integers
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloat compare float with the bound
+func compareFloat(a float64, bound string) (int, bool) {
+	b, err := strconv.ParseFloat(bound, 64)
+	if err != nil || math.IsNaN(a) || math.IsNaN(b) {
+		return 0, false
+	}
+	switch {
+	case a < b:
+		return -1, true
+	case a > b:
+		return 1, true
+	}
+	return 0, true
 }
 
 // IsDigits check for digits
@@ -346,7 +353,7 @@ func IsDigits(val reflect.Value, args ...string) bool {
 	case reflect.Int32:
 		fallthrough
 	case reflect.Int64:
-		value = strconv.FormatInt(val.Int(), 10)
+		value = strings.TrimPrefix(strconv.FormatInt(val.Int(), 10), "-")
 	case reflect.Uint:
 		fallthrough
 	case reflect.Uint8:
@@ -357,6 +364,8 @@ func IsDigits(val reflect.Value, args ...string) bool {
 		fallthrough
 	case reflect.Uint64:
 		value = strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		value = strconv.FormatFloat(math.Abs(val.Float()), 'f', -1, 64)
 	case reflect.Slice:
 		for i := 0; i < val.Len(); i++ {
 			if !IsDigits(val.Index(i), args...) {
@@ -393,3 +402,167 @@ func IsDigits(val reflect.Value, args ...string) bool {
 func IsNotNullValid(val reflect.Value, args ...string) bool {
 	return val.Kind() == reflect.Ptr && !val.IsNil()
 }
+
+// collectionLen length of collection. Returns false if value is not a collection
+func collectionLen(val reflect.Value) (int, bool) {
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return val.Len(), true
+	}
+	return 0, false
+}
+
+// IsMinItemsValid check min count of collection items
+func IsMinItemsValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return true
+		}
+		val = val.Elem()
+	}
+	min, err := strconv.Atoi(args[0])
+	if err != nil {
+		return false
+	}
+	l, ok := collectionLen(val)
+	return ok && l >= min
+}
+
+// IsMaxItemsValid check max count of collection items
+func IsMaxItemsValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return true
+		}
+		val = val.Elem()
+	}
+	max, err := strconv.Atoi(args[0])
+	if err != nil {
+		return false
+	}
+	l, ok := collectionLen(val)
+	return ok && l <= max
+}
+
+// IsLenValid check exact count of collection items or string length
+// Can specify several lengths separated by comma
+func IsLenValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return true
+		}
+		val = val.Elem()
+	}
+	l, ok := collectionLen(val)
+	if !ok {
+		if val.Kind() != reflect.String {
+			return false
+		}
+		l = len([]rune(val.String()))
+	}
+	for _, length := range strings.Split(args[0], ",") {
+		ll, err := strconv.Atoi(length)
+		if err != nil {
+			return false
+		}
+		if ll == l {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUniqueValid check if collection items or map values are unique
+func IsUniqueValid(val reflect.Value, args ...string) bool {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return true
+		}
+		val = val.Elem()
+	}
+	var items []reflect.Value
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		items = make([]reflect.Value, val.Len())
+		for i := range items {
+			items[i] = val.Index(i)
+		}
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			items = append(items, iter.Value())
+		}
+	default:
+		return false
+	}
+	if len(items) < 2 {
+		return true
+	}
+	if _, ok := scalarKey(items[0]); ok {
+		seen := make(map[interface{}]struct{}, len(items))
+		for _, item := range items {
+			key, _ := scalarKey(item)
+			if _, ok := seen[key]; ok {
+				return false
+			}
+			seen[key] = struct{}{}
+		}
+		return true
+	}
+	if !items[0].CanInterface() {
+		return true
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if reflect.DeepEqual(items[i].Interface(), items[j].Interface()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// scalarKey comparable key of scalar value. Works for values of unexported fields
+func scalarKey(val reflect.Value) (interface{}, bool) {
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), true
+	case reflect.Bool:
+		return val.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return val.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	case reflect.Complex64, reflect.Complex128:
+		return val.Complex(), true
+	}
+	return nil, false
+}
+
+// IsNonEmptyValid check if collection or string is not empty
+func IsNonEmptyValid(val reflect.Value, args ...string) bool {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return false
+		}
+		val = val.Elem()
+	}
+	if l, ok := collectionLen(val); ok {
+		return l > 0
+	}
+	if val.Kind() == reflect.String {
+		return val.Len() > 0
+	}
+	return !val.IsZero()
+}
@@ -0,0 +1,292 @@
+package v
+
+import (
+	"fmt"
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registered schemas. Struct type -> field index -> rules
+var schemas = make(map[reflect.Type]map[int]ValidationRules)
+
+// Lock of registered schemas. Plans are compiled concurrently with Compile and Reset
+var schemasLock sync.RWMutex
+
+// SchemaBuilder builder of validation rules for struct type without tags
+// Rules are registered by Compile and appended to rules of valid tag in the same plan
+type SchemaBuilder[T any] struct {
+	// Struct type
+	t reflect.Type
+	// Field index -> rules
+	fields map[int]ValidationRules
+	// Error of schema definition
+	e porterr.IError
+	// Rules are changed after Compile
+	changed bool
+}
+
+// FieldBuilder builder of validation rules for struct field of type F
+type FieldBuilder[T any, F any] struct {
+	// Schema of struct
+	schema *SchemaBuilder[T]
+	// Field index in struct
+	index int
+}
+
+// Schema get builder of validation rules for struct type
+// Example
+//
+//	s := v.Schema[User]()
+//	v.Field(s, func(u *User) *string { return &u.Name }).Required().MinLen(3).Rx("^[a-z]+$")
+//	e := s.Compile()
+func Schema[T any]() *SchemaBuilder[T] {
+	s := &SchemaBuilder[T]{t: reflect.TypeOf((*T)(nil)).Elem(), fields: make(map[int]ValidationRules), changed: true}
+	if s.t.Kind() != reflect.Struct {
+		s.e = porterr.New(porterr.PortErrorArgument, "Type struct required. Type "+s.t.String()+" received")
+	}
+	return s
+}
+
+// Field get builder of field selected by function returning pointer to the field
+// Type of field is checked by compiler
+// Example: v.Field(s, func(u *User) *string { return &u.Name })
+func Field[T any, F any](s *SchemaBuilder[T], selector func(*T) *F) *FieldBuilder[T, F] {
+	f := &FieldBuilder[T, F]{schema: s, index: -1}
+	if s.t.Kind() != reflect.Struct {
+		return f
+	}
+	var value T
+	base := reflect.ValueOf(&value).Pointer()
+	if ptr := selector(&value); ptr != nil {
+		addr := reflect.ValueOf(ptr).Pointer()
+		ft := reflect.TypeOf(ptr).Elem()
+		for i := 0; i < s.t.NumField(); i++ {
+			field := s.t.Field(i)
+			if base+field.Offset == addr && field.Type == ft {
+				f.index = i
+				break
+			}
+		}
+	}
+	if f.index < 0 && s.e == nil {
+		s.e = porterr.New(porterr.PortErrorArgument, "Selector must return pointer to field of "+s.t.String())
+	}
+	return f
+}
+
+// Compile register rules of schema and compile validation plan
+// Compile must be called before Validate and after rules are changed
+// Rules of previous Compile for the type are replaced, so schema could be defined again
+// Schema with errors is not registered
+func (s *SchemaBuilder[T]) Compile() porterr.IError {
+	if s.e != nil {
+		return s.e
+	}
+	var fields = make(map[int]ValidationRules, len(s.fields))
+	for index, rules := range s.fields {
+		fields[index] = append(ValidationRules(nil), rules...)
+	}
+	previous := registerSchema(s.t, fields)
+	if e := CompileStruct(reflect.Zero(reflect.PtrTo(s.t)).Interface()); e != nil {
+		registerSchema(s.t, previous)
+		return e
+	}
+	s.changed = false
+	return nil
+}
+
+// Validate validate struct with rules of schema and tags
+// Returns error if rules are changed after Compile
+func (s *SchemaBuilder[T]) Validate(v *T) porterr.IError {
+	if s.changed {
+		return porterr.New(porterr.PortErrorArgument, "Schema of "+s.t.String()+" is not compiled. Call Compile after rules are changed")
+	}
+	return ValidateStruct(v)
+}
+
+// Reset remove all rules of schema
+func (s *SchemaBuilder[T]) Reset() {
+	s.fields, s.changed = make(map[int]ValidationRules), true
+	registerSchema(s.t, nil)
+}
+
+// registerSchema replace rules of struct type and reset compiled plans. Nil rules remove the schema
+// Returns previous rules of the type
+func registerSchema(t reflect.Type, fields map[int]ValidationRules) map[int]ValidationRules {
+	schemasLock.Lock()
+	previous := schemas[t]
+	if fields == nil {
+		delete(schemas, t)
+	} else {
+		schemas[t] = fields
+	}
+	schemasLock.Unlock()
+	resetPlans()
+	return previous
+}
+
+// Rule add rule with arguments to the field. Aliases and custom rules could be used
+// Arguments are joined by comma
+func (f *FieldBuilder[T, F]) Rule(name string, args ...string) *FieldBuilder[T, F] {
+	return f.add(ValidationRule{Name: name, Args: joinArgs(args)})
+}
+
+// Not add negated rule with arguments to the field
+func (f *FieldBuilder[T, F]) Not(name string, args ...string) *FieldBuilder[T, F] {
+	return f.add(ValidationRule{Name: name, Args: joinArgs(args), Not: true})
+}
+
+// Required add required rule
+func (f *FieldBuilder[T, F]) Required() *FieldBuilder[T, F] {
+	return f.Rule("required")
+}
+
+// NotNull add notnull rule
+func (f *FieldBuilder[T, F]) NotNull() *FieldBuilder[T, F] {
+	return f.Rule("notnull")
+}
+
+// Min add min rule with minimal value of the field. Use MinLen for length of strings
+func (f *FieldBuilder[T, F]) Min(bound F) *FieldBuilder[T, F] {
+	return f.Rule("min", formatValue(bound))
+}
+
+// Max add max rule with maximal value of the field. Use MaxLen for length of strings
+func (f *FieldBuilder[T, F]) Max(bound F) *FieldBuilder[T, F] {
+	return f.Rule("max", formatValue(bound))
+}
+
+// MinLen add min rule with minimal length of string
+func (f *FieldBuilder[T, F]) MinLen(length int) *FieldBuilder[T, F] {
+	return f.Rule("min", strconv.Itoa(length))
+}
+
+// MaxLen add max rule with maximal length of string
+func (f *FieldBuilder[T, F]) MaxLen(length int) *FieldBuilder[T, F] {
+	return f.Rule("max", strconv.Itoa(length))
+}
+
+// Gt add gt rule
+func (f *FieldBuilder[T, F]) Gt(bound F) *FieldBuilder[T, F] {
+	return f.Rule("gt", formatValue(bound))
+}
+
+// Lt add lt rule
+func (f *FieldBuilder[T, F]) Lt(bound F) *FieldBuilder[T, F] {
+	return f.Rule("lt", formatValue(bound))
+}
+
+// Range add range rule. Example: Range("1:50"), Range("[0,1)")
+func (f *FieldBuilder[T, F]) Range(interval string) *FieldBuilder[T, F] {
+	return f.Rule("range", interval)
+}
+
+// Enum add enum rule with allowed values
+func (f *FieldBuilder[T, F]) Enum(values ...F) *FieldBuilder[T, F] {
+	var args = make([]string, len(values))
+	for i := range values {
+		args[i] = formatValue(values[i])
+	}
+	return f.Rule("enum", args...)
+}
+
+// Rx add regular expression rule. Pattern is not parsed as tag, so it could contain any chars
+func (f *FieldBuilder[T, F]) Rx(pattern string) *FieldBuilder[T, F] {
+	return f.Rule("rx", pattern)
+}
+
+// Digit add digit rule with optional lengths
+func (f *FieldBuilder[T, F]) Digit(lengths ...int) *FieldBuilder[T, F] {
+	return f.Rule("digit", formatInts(lengths)...)
+}
+
+// Len add len rule with allowed lengths
+func (f *FieldBuilder[T, F]) Len(lengths ...int) *FieldBuilder[T, F] {
+	return f.Rule("len", formatInts(lengths)...)
+}
+
+// MinItems add minItems rule
+func (f *FieldBuilder[T, F]) MinItems(count int) *FieldBuilder[T, F] {
+	return f.Rule("minItems", strconv.Itoa(count))
+}
+
+// MaxItems add maxItems rule
+func (f *FieldBuilder[T, F]) MaxItems(count int) *FieldBuilder[T, F] {
+	return f.Rule("maxItems", strconv.Itoa(count))
+}
+
+// Unique add unique rule
+func (f *FieldBuilder[T, F]) Unique() *FieldBuilder[T, F] {
+	return f.Rule("unique")
+}
+
+// NonEmpty add nonempty rule
+func (f *FieldBuilder[T, F]) NonEmpty() *FieldBuilder[T, F] {
+	return f.Rule("nonempty")
+}
+
+// Dive apply following rules to elements of slice
+// Example: v.Dive(v.Field(s, func(u *User) *[]string { return &u.Tags })).Enum("a", "b")
+func Dive[T any, E any](f *FieldBuilder[T, []E]) *FieldBuilder[T, E] {
+	f.Rule(ruleDive)
+	return &FieldBuilder[T, E]{schema: f.schema, index: f.index}
+}
+
+// DiveKeys apply following rules to map keys
+func DiveKeys[T any, K comparable, E any](f *FieldBuilder[T, map[K]E]) *FieldBuilder[T, K] {
+	f.Rule(ruleDive, diveKeys)
+	return &FieldBuilder[T, K]{schema: f.schema, index: f.index}
+}
+
+// DiveValues apply following rules to map values
+func DiveValues[T any, K comparable, E any](f *FieldBuilder[T, map[K]E]) *FieldBuilder[T, E] {
+	f.Rule(ruleDive)
+	return &FieldBuilder[T, E]{schema: f.schema, index: f.index}
+}
+
+// add append rule to the field
+func (f *FieldBuilder[T, F]) add(rule ValidationRule) *FieldBuilder[T, F] {
+	if f.index < 0 {
+		return f
+	}
+	f.schema.fields[f.index] = append(f.schema.fields[f.index], rule)
+	f.schema.changed = true
+	return f
+}
+
+// schemaRules rules of struct field defined in registered schema
+func schemaRules(te reflect.Type, index int) ValidationRules {
+	schemasLock.RLock()
+	defer schemasLock.RUnlock()
+	return schemas[te][index]
+}
+
+// joinArgs join arguments of rule
+func joinArgs(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return []string{strings.Join(args, ",")}
+}
+
+// formatValue format value of field as argument. Pointers are dereferenced unless they implement fmt.Stringer
+func formatValue(value interface{}) string {
+	if _, ok := value.(fmt.Stringer); !ok {
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			return formatValue(rv.Elem().Interface())
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// formatInts format integers as arguments
+func formatInts(values []int) []string {
+	var args = make([]string, len(values))
+	for i := range values {
+		args[i] = strconv.Itoa(values[i])
+	}
+	return args
+}
@@ -0,0 +1,87 @@
+package v
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// Type of driver.Valuer interface
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// UnwrapValue get underlying value of sql.Null* types and driver.Valuer
+// Value is returned as pointer to underlying value, so null value is nil pointer
+// Other values are returned as is
+func UnwrapValue(val reflect.Value) reflect.Value {
+	val, _ = unwrapValue(val)
+	return val
+}
+
+// unwrapValue get underlying value of sql.Null* type, driver.Valuer or pointer to them
+// Zero arrays like [16]byte UUID are treated as nil even if Value() is not empty
+// Returns false if value is not unwrapped
+func unwrapValue(val reflect.Value) (reflect.Value, bool) {
+	if !val.IsValid() || !val.CanInterface() || !isValuer(val.Type()) {
+		return val, false
+	}
+	t := val.Type()
+	if t.Kind() == reflect.Ptr {
+		if isNullType(t.Elem()) {
+			if val.IsNil() {
+				return reflect.Zero(reflect.PtrTo(t.Elem().Field(0).Type)), true
+			}
+			return unwrapNull(val.Elem()), true
+		}
+		if val.IsNil() {
+			return val, false
+		}
+	} else if isNullType(t) {
+		return unwrapNull(val), true
+	} else if t.Kind() == reflect.Array && val.IsZero() {
+		return reflect.Zero(reflect.PtrTo(t)), true
+	}
+	var valuer driver.Valuer
+	if t.Implements(valuerType) {
+		valuer = val.Interface().(driver.Valuer)
+	} else {
+		valuer = pointerTo(val).Interface().(driver.Valuer)
+	}
+	value, err := valuer.Value()
+	if err != nil {
+		return val, false
+	}
+	if value == nil {
+		return reflect.Zero(reflect.PtrTo(t)), true
+	}
+	return pointerTo(reflect.ValueOf(value)), true
+}
+
+// isValuer check if type or pointer to type implements driver.Valuer
+func isValuer(t reflect.Type) bool {
+	return t.Implements(valuerType) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(valuerType))
+}
+
+// pointerTo get pointer to copy of value
+func pointerTo(val reflect.Value) reflect.Value {
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	return ptr
+}
+
+// isNullType check if struct is null type like sql.NullString
+// Null type has two fields: value and Valid flag
+func isNullType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	valid, ok := t.FieldByName("Valid")
+	return ok && valid.Type.Kind() == reflect.Bool && valid.Index[0] == 1
+}
+
+// unwrapNull get value of null type
+func unwrapNull(val reflect.Value) reflect.Value {
+	value := val.Field(0)
+	if !val.Field(1).Bool() {
+		return reflect.Zero(reflect.PtrTo(value.Type()))
+	}
+	return pointerTo(value)
+}
@@ -0,0 +1,284 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"strconv"
+	"strings"
+)
+
+// Tag grammar tokens
+const (
+	// Rules separator. All rules must be valid
+	tagAnd = ';'
+	// Alternatives separator. At least one alternative must be valid
+	tagOr = '|'
+	// Negation of the rule or group
+	tagNot = '!'
+	// Start of group
+	tagGroupStart = '('
+	// End of group
+	tagGroupEnd = ')'
+	// Rule arguments separator
+	tagArgs = '~'
+)
+
+// validName check if name of alias, enum, transition or union could be used in tag
+// Name must not be empty and must not contain tag grammar chars and comma separating arguments
+func validName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, string([]byte{tagAnd, tagOr, tagNot, tagGroupStart, tagGroupEnd, tagArgs, ','}))
+}
+
+// tagParser validation tag parser state
+type tagParser struct {
+	// Validation tag
+	tag string
+	// Current position
+	pos int
+	// Depth of groups
+	depth int
+}
+
+// ParseValidTag parse validation tag for rule and arguments
+// Example
+// valid:"rx~[0-5]+;range~1-50;enum~5,10,15,20,25"`
+// Rules could be combined with alternatives, negations and groups
+// valid:"required;(rx~^[a-f0-9-]{36}$|digit);!enum~admin,root"`
+// Malformed part of tag is ignored
+func ParseValidTag(validTag string) ValidationRules {
+	rules, _ := parseValidTag(validTag)
+	return rules
+}
+
+// parseValidTag parse validation tag and return parse error if exists
+func parseValidTag(validTag string) (ValidationRules, porterr.IError) {
+	if validTag == "" {
+		return nil, nil
+	}
+	p := &tagParser{tag: validTag}
+	rules, e := p.parseRules()
+	if e == nil && p.pos < len(p.tag) {
+		e = p.error("unexpected '" + string(p.tag[p.pos]) + "'")
+	}
+	return rules, e
+}
+
+// parseRules parse list of rules separated by ';'
+func (p *tagParser) parseRules() (ValidationRules, porterr.IError) {
+	var rules ValidationRules
+	for p.pos < len(p.tag) {
+		switch p.tag[p.pos] {
+		case tagAnd:
+			p.pos++
+			continue
+		case tagGroupEnd:
+			if p.depth > 0 {
+				return rules, nil
+			}
+			return rules, p.error("unexpected ')'")
+		}
+		rule, e := p.parseAlternatives()
+		if e != nil {
+			return rules, e
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseAlternatives parse list of rules separated by '|'
+func (p *tagParser) parseAlternatives() (ValidationRule, porterr.IError) {
+	var alternatives []ValidationRules
+	for {
+		rule, e := p.parseFactor()
+		if e != nil {
+			return rule, e
+		}
+		if rule.isGroup() {
+			alternatives = append(alternatives, rule.Alternatives[0])
+		} else {
+			alternatives = append(alternatives, ValidationRules{rule})
+		}
+		if p.pos >= len(p.tag) || p.tag[p.pos] != tagOr {
+			break
+		}
+		p.pos++
+	}
+	if len(alternatives) == 1 && len(alternatives[0]) == 1 {
+		return alternatives[0][0], nil
+	}
+	return ValidationRule{Alternatives: alternatives}, nil
+}
+
+// parseFactor parse single rule or group with optional negation
+func (p *tagParser) parseFactor() (ValidationRule, porterr.IError) {
+	var not bool
+	for p.pos < len(p.tag) && p.tag[p.pos] == tagNot {
+		not = !not
+		p.pos++
+	}
+	if p.pos < len(p.tag) && p.tag[p.pos] == tagGroupStart {
+		start := p.pos
+		p.pos++
+		p.depth++
+		rules, e := p.parseRules()
+		if e != nil {
+			return ValidationRule{}, e
+		}
+		if p.pos >= len(p.tag) {
+			p.pos = start
+			return ValidationRule{}, p.error("group is not closed")
+		}
+		p.pos++
+		p.depth--
+		if len(rules) == 0 {
+			return ValidationRule{}, p.error("empty group")
+		}
+		if len(rules) == 1 {
+			rules[0].Not = rules[0].Not != not
+			return rules[0], nil
+		}
+		return ValidationRule{Not: not, Alternatives: []ValidationRules{rules}}, nil
+	}
+	rule, e := p.parseRule()
+	rule.Not = not
+	return rule, e
+}
+
+// parseRule parse rule name and arguments
+func (p *tagParser) parseRule() (ValidationRule, porterr.IError) {
+	var rule ValidationRule
+	start := p.pos
+	for p.pos < len(p.tag) && !p.isRuleEnd() && p.tag[p.pos] != tagArgs && p.tag[p.pos] != tagGroupEnd {
+		p.pos++
+	}
+	rule.Name = p.tag[start:p.pos]
+	if rule.Name == "" {
+		return rule, p.error("rule name expected")
+	}
+	if p.pos < len(p.tag) && p.tag[p.pos] == tagArgs {
+		p.pos++
+		rule.Args = []string{p.parseArgs(intervalArgs[rule.Name])}
+	}
+	return rule, nil
+}
+
+// Rules which arguments contain intervals like [0,1) or (0,]
+var intervalArgs = map[string]bool{"range": true}
+
+// parseArgs parse rule arguments
+// Arguments ends with ';', '|' or ')' which closes the group
+// Parentheses and brackets inside arguments must be balanced, backslash escapes next char
+// Intervals could be closed with any of ')' or ']'
+func (p *tagParser) parseArgs(intervals bool) string {
+	start := p.pos
+	var parentheses int
+	var bracket bool
+	for p.pos < len(p.tag) {
+		switch c := p.tag[p.pos]; {
+		case c == '\\':
+			p.pos++
+		case intervals && (c == '[' || c == tagGroupStart):
+			parentheses++
+		case intervals && (c == ']' || c == tagGroupEnd) && parentheses > 0:
+			parentheses--
+		case bracket:
+			bracket = c != ']'
+		case c == '[':
+			bracket = true
+		case c == tagGroupStart:
+			parentheses++
+		case c == tagGroupEnd && parentheses > 0:
+			parentheses--
+		case parentheses == 0 && p.isRuleEnd():
+			return p.tag[start:p.pos]
+		}
+		p.pos++
+	}
+	if p.pos > len(p.tag) {
+		p.pos = len(p.tag)
+	}
+	return p.tag[start:]
+}
+
+// isRuleEnd check if current char ends the rule
+func (p *tagParser) isRuleEnd() bool {
+	switch p.tag[p.pos] {
+	case tagAnd, tagOr:
+		return true
+	case tagGroupEnd:
+		return p.depth > 0
+	}
+	return false
+}
+
+// error prepare parse error
+func (p *tagParser) error(message string) porterr.IError {
+	return porterr.New(porterr.PortErrorParser, "Invalid valid tag '"+p.tag+"': "+message+" at position "+strconv.Itoa(p.pos))
+}
+
+// isGroup check if rule is a group of rules without alternatives
+func (r ValidationRule) isGroup() bool {
+	return !r.Not && r.Name == "" && r.Alias == "" && len(r.Alternatives) == 1
+}
+
+// String rule in tag notation
+func (r ValidationRule) String() string {
+	var b strings.Builder
+	r.write(&b)
+	return b.String()
+}
+
+// write rule in tag notation
+func (r ValidationRule) write(b *strings.Builder) {
+	if r.Not {
+		b.WriteByte(tagNot)
+	}
+	if r.Alias != "" {
+		b.WriteString(r.Alias)
+		return
+	}
+	if r.Name != "" {
+		b.WriteString(r.Name)
+		if len(r.Args) > 0 {
+			b.WriteByte(tagArgs)
+			b.WriteString(strings.Join(r.Args, ","))
+		}
+		return
+	}
+	group := r.Not || len(r.Alternatives) == 1
+	if group {
+		b.WriteByte(tagGroupStart)
+	}
+	for i, alternative := range r.Alternatives {
+		if i > 0 {
+			b.WriteByte(tagOr)
+		}
+		if len(alternative) > 1 && len(r.Alternatives) > 1 {
+			b.WriteByte(tagGroupStart)
+			alternative.write(b)
+			b.WriteByte(tagGroupEnd)
+		} else {
+			alternative.write(b)
+		}
+	}
+	if group {
+		b.WriteByte(tagGroupEnd)
+	}
+}
+
+// String rules in tag notation
+func (r ValidationRules) String() string {
+	var b strings.Builder
+	r.write(&b)
+	return b.String()
+}
+
+// write rules in tag notation
+func (r ValidationRules) write(b *strings.Builder) {
+	for i, rule := range r {
+		if i > 0 {
+			b.WriteByte(tagAnd)
+		}
+		rule.write(b)
+	}
+}
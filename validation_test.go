@@ -1,7 +1,23 @@
 package v
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/dimonrus/porterr"
+	"math"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -960,3 +976,1822 @@ func BenchmarkNestedLevel(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+func TestParseValidTagComposition(t *testing.T) {
+	t.Run("alternatives", func(t *testing.T) {
+		rules := ParseValidTag("required;rx~^(foo|bar)$|digit~4;max~10")
+		if len(rules) != 3 {
+			t.Fatal("must be 3 rules", rules)
+		}
+		if len(rules[1].Alternatives) != 2 || rules[1].Alternatives[0][0].Args[0] != "^(foo|bar)$" {
+			t.Fatal("wrong alternatives", rules[1])
+		}
+		if rules.String() != "required;rx~^(foo|bar)$|digit~4;max~10" {
+			t.Fatal("wrong string", rules.String())
+		}
+	})
+	t.Run("negation", func(t *testing.T) {
+		rules := ParseValidTag("!enum~admin,root")
+		if len(rules) != 1 || !rules[0].Not || rules[0].Name != "enum" {
+			t.Fatal("wrong negation", rules)
+		}
+	})
+	t.Run("groups", func(t *testing.T) {
+		rules := ParseValidTag("(min~3;max~5)|!(rx~[a-z]+|digit)")
+		if len(rules) != 1 || len(rules[0].Alternatives) != 2 {
+			t.Fatal("wrong groups", rules)
+		}
+		if len(rules[0].Alternatives[0]) != 2 || !rules[0].Alternatives[1][0].Not {
+			t.Fatal("wrong groups", rules)
+		}
+		if rules.String() != "(min~3;max~5)|!(rx~[a-z]+|digit)" {
+			t.Fatal("wrong string", rules.String())
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		for _, tag := range []string{"(required", "required)", "required||min~1", "()"} {
+			if _, e := parseValidTag(tag); e == nil {
+				t.Fatal("must be an error", tag)
+			}
+		}
+	})
+}
+
+func TestValidateComposition(t *testing.T) {
+	type Account struct {
+		Id    string `json:"id" valid:"required;rx~^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$|digit"`
+		Login string `json:"login" valid:"required;!enum~admin,root"`
+		Code  string `json:"code" valid:"(min~2;max~3)|enum~none"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		for _, a := range []Account{
+			{Id: "12345", Login: "user", Code: "ab"},
+			{Id: "0f8fad5b-d9cb-469f-a165-70867728950e", Login: "user", Code: "none"},
+		} {
+			if e := ValidateStruct(a); e != nil {
+				t.Fatal(e.GetDetails())
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		e := ValidateStruct(Account{Id: "abc", Login: "root", Code: "abcd"})
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		details := e.GetDetails()
+		if len(details) != 3 {
+			t.Fatal("must be 3 details", details)
+		}
+		for _, detail := range details {
+			t.Log(detail.Error())
+		}
+		if !strings.Contains(details[0].Error(), "digit") || !strings.Contains(details[2].Error(), "(min~2;max~3), enum~none") {
+			t.Fatal("alternatives must be listed")
+		}
+		if !strings.Contains(details[1].Error(), "!enum") {
+			t.Fatal("negation must be listed")
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		for _, s := range []interface{}{
+			struct {
+				Code string `valid:"rx~^a$|^b$"`
+			}{},
+			struct {
+				Code string `valid:"rx~^\\d+$|uuid"`
+			}{},
+			struct {
+				Code string `valid:"!unknown"`
+			}{},
+		} {
+			if e := CompileStruct(s); e == nil || !strings.Contains(e.Error(), "is not registered") {
+				t.Fatal("unknown rule must be reported", e)
+			}
+		}
+		if e := Var("c", "rx~^(a|b)$"); e == nil {
+			t.Fatal("must be an error")
+		}
+	})
+}
+
+func TestRegisterAlias(t *testing.T) {
+	defer UnregisterAlias("username")
+	defer UnregisterAlias("login")
+	if e := RegisterAlias("username", "required;min~3;max~32;rx~^[a-z0-9_]+$"); e != nil {
+		t.Fatal(e)
+	}
+	if e := RegisterAlias("login", "username;!enum~admin,root"); e != nil {
+		t.Fatal(e)
+	}
+	type User struct {
+		Name  string `json:"name" valid:"username"`
+		Login string `json:"login" valid:"login|enum~admin"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		if e := ValidateStruct(User{Name: "john_doe", Login: "admin"}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		e := ValidateStruct(User{Name: "Jo", Login: "root"})
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		details := e.GetDetails()
+		if len(details) != 2 {
+			t.Fatal("must be 2 details", details)
+		}
+		if details[0].Error() != "Invalid validation for username rule on field: name" {
+			t.Fatal("alias name expected", details[0].Error())
+		}
+		if !strings.Contains(details[1].Error(), "login, enum~admin") {
+			t.Fatal("alias name expected", details[1].Error())
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		if e := RegisterAlias("required", "min~1"); e == nil {
+			t.Fatal("must be an error")
+		}
+		for _, name := range []string{"immutable", "oneOf", "anyOf", "exclusive", "dive", "union"} {
+			if e := RegisterAlias(name, "min~1"); e == nil {
+				t.Fatal("reserved rule must not be an alias", name)
+			}
+		}
+		if e := RegisterAlias("bad;name", "min~1"); e == nil {
+			t.Fatal("must be an error")
+		}
+		if e := RegisterAlias("bad,name", "min~1"); e == nil {
+			t.Fatal("comma must not be in name")
+		}
+		if e := RegisterAlias("broken", "(min~1"); e == nil {
+			t.Fatal("must be an error")
+		}
+	})
+	t.Run("cycle", func(t *testing.T) {
+		defer UnregisterAlias("first")
+		defer UnregisterAlias("second")
+		_ = RegisterAlias("first", "required;second")
+		_ = RegisterAlias("second", "max~2|first")
+		e := CompileStruct(struct {
+			Name string `valid:"first"`
+		}{})
+		if e == nil || !strings.Contains(e.Error(), "first -> second -> first") {
+			t.Fatal("cycle must be detected", e)
+		}
+		e = ValidateStruct(struct {
+			Name string `valid:"first"`
+		}{})
+		if e == nil || e.GetHTTP() != 500 {
+			t.Fatal("compile error expected", e)
+		}
+	})
+}
+
+func TestCompileStruct(t *testing.T) {
+	type Broken struct {
+		Name string `valid:"required;(min~1"`
+	}
+	type Wrapper struct {
+		Items []Broken
+	}
+	if e := CompileStruct(&Broken{}); e == nil {
+		t.Fatal("must be an error")
+	}
+	if e := ValidateStruct(Wrapper{Items: []Broken{{}}}); e == nil || e.GetHTTP() != 500 {
+		t.Fatal("nested compile error expected", e)
+	}
+	if e := CompileStruct(TestValidationStruct{}); e != nil {
+		t.Fatal(e)
+	}
+}
+
+func TestCollectionRules(t *testing.T) {
+	type Collection struct {
+		Tags   []string            `json:"tags" valid:"minItems~1;maxItems~3;unique;dive;max~5"`
+		Codes  [2]int              `json:"codes" valid:"len~2;dive;range~1:10"`
+		Matrix [][]string          `json:"matrix" valid:"nonempty;dive;len~2;dive;digit"`
+		Attrs  map[string][]int    `json:"attrs" valid:"maxItems~2;dive~keys;rx~^[a-z]+$;dive;minItems~1;dive;min~0"`
+		Names  *[]string           `json:"names" valid:"unique"`
+		Empty  map[string]struct{} `json:"empty" valid:"nonempty"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		c := Collection{
+			Tags:   []string{"go", "rust"},
+			Codes:  [2]int{1, 10},
+			Matrix: [][]string{{"1", "2"}, {"3", "4"}},
+			Attrs:  map[string][]int{"a": {0, 1}, "b": {2}},
+			Names:  &[]string{"a", "b"},
+			Empty:  map[string]struct{}{"a": {}},
+		}
+		if e := ValidateStruct(c); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		c := Collection{
+			Tags:   []string{"go", "go", "haskell", "c"},
+			Codes:  [2]int{0, 10},
+			Matrix: [][]string{{"1", "2"}, {"3", "a"}, {"5"}},
+			Attrs:  map[string][]int{"A": {0}, "b": {}, "c": {-1}},
+			Names:  &[]string{"a", "a"},
+			Empty:  map[string]struct{}{},
+		}
+		e := ValidateStruct(c)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+			t.Log(detail.Error())
+		}
+		expected := []string{"tags", "tags", "tags[2]", "codes[0]", "matrix[1][1]", "matrix[2]", "attrs",
+			`attrs["A"]`, `attrs["b"]`, `attrs["c"][0]`, "names", "empty"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		if e := CompileStruct(struct {
+			Name string `valid:"dive;max~2"`
+		}{}); e == nil {
+			t.Fatal("dive on string must be an error")
+		}
+		if e := CompileStruct(struct {
+			Tags []string `valid:"dive~keys;max~2"`
+		}{}); e == nil {
+			t.Fatal("keys on slice must be an error")
+		}
+		if e := CompileStruct(struct {
+			Tags []string `valid:"nonempty|dive"`
+		}{}); e == nil {
+			t.Fatal("dive in alternatives must be an error")
+		}
+	})
+}
+
+type Address struct {
+	Zip  string `json:"zip" valid:"required;digit~5"`
+	City string `json:"city" valid:"required"`
+}
+
+type Customer struct {
+	Addresses map[string]Address        `json:"addresses" valid:"minItems~1;maxItems~3;dive~keys;enum~home,work,other"`
+	Backup    map[string]*Address       `json:"backup"`
+	Groups    map[int][]Address         `json:"groups"`
+	Nested    map[string]map[string]int `json:"nested" valid:"dive;dive~keys;len~2"`
+	Primary   *Address                  `json:"primary"`
+	History   []Address                 `json:"history"`
+}
+
+func TestMapValidation(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		c := Customer{
+			Addresses: map[string]Address{"home": {Zip: "12345", City: "Paris"}},
+			Backup:    map[string]*Address{"a": nil},
+			Nested:    map[string]map[string]int{"x": {"ab": 1}},
+		}
+		if e := ValidateStruct(&c); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		c := Customer{
+			Addresses: map[string]Address{"home": {Zip: "1234", City: "Paris"}, "moon": {Zip: "12345", City: "Moon"}},
+			Backup:    map[string]*Address{"a": {Zip: "12345"}},
+			Groups:    map[int][]Address{7: {{Zip: "12345", City: "Rome"}, {Zip: "x", City: "Rome"}}},
+			Nested:    map[string]map[string]int{"x": {"abc": 1}},
+			Primary:   &Address{City: "Oslo", Zip: "1"},
+			History:   []Address{{Zip: "12345"}},
+		}
+		e := ValidateStruct(&c)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{`addresses["home"].zip`, `addresses["moon"]`, `backup["a"].city`, `groups[7][1].zip`,
+			`nested["x"]["abc"]`, "primary.zip", "history[0].city"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		e := ValidateStruct(&Customer{})
+		if e == nil || e.GetDetails()[0].Origin().Name != "addresses" {
+			t.Fatal("entry count error expected", e)
+		}
+	})
+}
+
+type Payload struct {
+	Items    [2]Address             `json:"items"`
+	PItems   *[1]*Address           `json:"pItems"`
+	Any      interface{}            `json:"any"`
+	Stringer fmt.Stringer           `json:"stringer"`
+	List     []interface{}          `json:"list"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+type stringerAddress struct {
+	Address
+	Name string `json:"name" valid:"required"`
+}
+
+func (s stringerAddress) String() string {
+	return s.Name
+}
+
+func TestArrayInterfaceTraversal(t *testing.T) {
+	p := Payload{
+		Items:    [2]Address{{Zip: "12345", City: "Rome"}, {Zip: "1", City: "Rome"}},
+		PItems:   &[1]*Address{{Zip: "12345"}},
+		Any:      &Address{Zip: "12345"},
+		Stringer: stringerAddress{Address: Address{Zip: "12345", City: "Rome"}},
+		List:     []interface{}{1, "a", Address{City: "Rome", Zip: "12345"}, Address{City: "Rome"}},
+		Values:   map[string]interface{}{"a": Address{Zip: "12345"}, "b": nil},
+	}
+	e := ValidateStruct(p)
+	if e == nil {
+		t.Fatal("must be an error")
+	}
+	var names []string
+	for _, detail := range e.GetDetails() {
+		names = append(names, detail.Origin().Name)
+	}
+	expected := []string{"items[1].zip", "pItems[0].city", "any.city", "stringer.name", "list[3].zip",
+		`values["a"].city`}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Fatal("wrong error names", names)
+	}
+}
+
+type Category struct {
+	Name     string      `json:"name" valid:"required"`
+	Parent   *Category   `json:"parent"`
+	Children []*Category `json:"children"`
+}
+
+func TestRecursiveStructures(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		root := &Category{Name: "root"}
+		child := &Category{Parent: root}
+		root.Children = []*Category{child, root}
+		e := ValidateStruct(root)
+		if e == nil || len(e.GetDetails()) != 1 || e.GetDetails()[0].Origin().Name != "children[0].name" {
+			t.Fatal("one error expected", e)
+		}
+		child.Name = "child"
+		if e = ValidateStruct(root); e != nil {
+			t.Fatal("parent back reference must be valid", e)
+		}
+	})
+	t.Run("shared", func(t *testing.T) {
+		shared := &Category{}
+		two := struct {
+			A *Category `json:"a"`
+			B *Category `json:"b"`
+		}{A: shared, B: shared}
+		e := ValidateStruct(two)
+		if e == nil || len(e.GetDetails()) != 2 || e.GetDetails()[1].Origin().Name != "b.name" {
+			t.Fatal("shared reference must be validated on each path", e)
+		}
+	})
+	t.Run("interface_cycle", func(t *testing.T) {
+		list := []interface{}{nil}
+		list[0] = list
+		st := struct {
+			List []interface{} `json:"list"`
+		}{List: list}
+		if e := ValidateStruct(st); e != nil {
+			t.Fatal(e)
+		}
+	})
+	t.Run("dag", func(t *testing.T) {
+		type Node struct {
+			Name  string `json:"name" valid:"required"`
+			Left  *Node  `json:"left"`
+			Right *Node  `json:"right"`
+		}
+		node := &Node{}
+		for i := 0; i < 30; i++ {
+			node = &Node{Name: "node", Left: node, Right: node}
+		}
+		e := ValidateStruct(node)
+		if e == nil || len(e.GetDetails()) != 31 {
+			t.Fatal("error of shared node must be reported once for each reference", e)
+		}
+		if name := e.GetDetails()[30].Origin().Name; name != "right."+strings.Repeat("left.", 29)+"name" {
+			t.Fatal("wrong error name", name)
+		}
+	})
+	t.Run("depth", func(t *testing.T) {
+		defer func(depth int) { MaxDepth = depth }(MaxDepth)
+		MaxDepth = 3
+		root := &Category{Name: "1", Children: []*Category{{Name: "2", Children: []*Category{{Name: "3", Children: []*Category{{Name: "4"}}}}}}}
+		e := ValidateStruct(root)
+		if e == nil {
+			t.Fatal("depth error expected")
+		}
+		detail := e.GetDetails()[0].Origin()
+		if detail.Name != "children[0].children[0].children[0]" || detail.GetCode() != porterr.PortErrorRecursion {
+			t.Fatal("wrong depth error", detail)
+		}
+		t.Log(detail.Message)
+	})
+}
+
+type Audit struct {
+	CreatedBy string `json:"createdBy,omitempty" valid:"required"`
+}
+
+type audit struct {
+	UpdatedBy string `json:"updatedBy" valid:"required"`
+}
+
+type Identity struct {
+	Id int `json:"id" valid:"required"`
+}
+
+type Document struct {
+	*Identity `valid:"required"`
+	Audit
+	audit
+	*Address `valid:"-"`
+	Meta     Audit `json:"meta"`
+	Named    `json:"named"`
+	Title    string `json:"title,omitempty" valid:"required"`
+}
+
+type Named struct {
+	Name string `json:"name" valid:"required"`
+}
+
+func TestEmbeddedStruct(t *testing.T) {
+	t.Run("promoted", func(t *testing.T) {
+		e := ValidateStruct(Document{Identity: &Identity{}, Address: &Address{}})
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{"Identity", "id", "createdBy", "updatedBy", "meta.createdBy", "named.name", "title"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("embedding_rules", func(t *testing.T) {
+		e := ValidateStruct(Document{Audit: Audit{CreatedBy: "a"}, audit: audit{UpdatedBy: "b"},
+			Meta: Audit{CreatedBy: "c"}, Named: Named{Name: "d"}, Title: "e"})
+		if e == nil || len(e.GetDetails()) != 1 || e.GetDetails()[0].Origin().Name != "Identity" {
+			t.Fatal("required embedded pointer error expected", e)
+		}
+	})
+}
+
+type privateData struct {
+	Code string `json:"code" valid:"required"`
+}
+
+type WithUnexported struct {
+	Name    string `json:"name" valid:"required"`
+	secret  string `valid:"required;min~3"`
+	nested  privateData
+	pointer *privateData
+	list    []privateData
+	tags    []string `valid:"unique"`
+}
+
+func TestUnexportedFields(t *testing.T) {
+	s := WithUnexported{
+		Name:    "name",
+		nested:  privateData{},
+		pointer: &privateData{},
+		list:    []privateData{{}},
+		tags:    []string{"a", "a"},
+	}
+	t.Run("skipped", func(t *testing.T) {
+		if e := ValidateStruct(s); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("opt_in", func(t *testing.T) {
+		ValidateUnexported = true
+		defer func() { ValidateUnexported = false }()
+		e := ValidateStruct(&s)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{"secret", "secret", "nested.code", "pointer.code", "list[0].code", "tags"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("built_in_rules", func(t *testing.T) {
+		ValidateUnexported = true
+		defer func() { ValidateUnexported = false }()
+		type private struct {
+			amount *big.Int       `valid:"min~10;positive;enum~10,20"`
+			items  []interface{}  `valid:"dive;required"`
+			date   time.Time      `valid:"past"`
+			name   sql.NullString `valid:"min~2"`
+			tags   []string       `valid:"unique"`
+		}
+		valid := private{
+			amount: big.NewInt(20),
+			items:  []interface{}{"a", 1},
+			date:   time.Now().Add(-time.Hour),
+			name:   sql.NullString{String: "name", Valid: true},
+			tags:   []string{"a", "b"},
+		}
+		if e := ValidateStruct(valid); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateStruct(&valid); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		invalid := private{
+			amount: big.NewInt(-1),
+			items:  []interface{}{"a", nil},
+			date:   time.Now().Add(time.Hour),
+			name:   sql.NullString{String: "x", Valid: true},
+			tags:   []string{"a", "a"},
+		}
+		expected := "amount:min amount:positive amount:enum items[1]:required date:past name:min tags:unique"
+		for _, v := range []interface{}{invalid, &invalid} {
+			e := ValidateStruct(v)
+			if e == nil {
+				t.Fatal("must be an error")
+			}
+			var names []string
+			for _, detail := range e.GetDetails() {
+				names = append(names, detail.Origin().Name+":"+strings.Fields(detail.Error())[3])
+			}
+			if strings.Join(names, " ") != expected {
+				t.Fatal("wrong errors", names)
+			}
+		}
+	})
+}
+
+type TimeStruct struct {
+	Birthday  time.Time     `json:"birthday" valid:"after~1900-01-01;past"`
+	Expires   *time.Time    `json:"expires" valid:"future;before~now+8760h"`
+	Event     time.Time     `json:"event" valid:"between~2000-01-01T00:00:00Z,2030-01-01T00:00:00Z"`
+	Seen      time.Time     `json:"seen" valid:"maxAge~90d"`
+	Timeout   time.Duration `json:"timeout" valid:"min~1s;max~1m"`
+	Retention time.Duration `json:"retention" valid:"range~1d:30d"`
+}
+
+func TestTimeRules(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	Now = func() time.Time { return now }
+	defer func() { Now = time.Now }()
+	t.Run("valid", func(t *testing.T) {
+		expires := now.Add(time.Hour)
+		s := TimeStruct{
+			Birthday:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			Expires:   &expires,
+			Event:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Seen:      now.AddDate(0, 0, -89),
+			Timeout:   30 * time.Second,
+			Retention: 7 * 24 * time.Hour,
+		}
+		if e := ValidateStruct(s); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateStruct(TimeStruct{Timeout: time.Second, Retention: 24 * time.Hour}); e != nil {
+			t.Fatal("zero time must be valid", e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		expires := now.Add(-time.Hour)
+		s := TimeStruct{
+			Birthday:  now.Add(time.Hour),
+			Expires:   &expires,
+			Event:     time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+			Seen:      now.AddDate(0, 0, -91),
+			Timeout:   2 * time.Minute,
+			Retention: time.Hour,
+		}
+		e := ValidateStruct(s)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{"birthday", "expires", "event", "seen", "timeout", "retention"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("parse", func(t *testing.T) {
+		d, err := ParseDuration("-1d12h")
+		if err != nil || d != -36*time.Hour {
+			t.Fatal("wrong duration", d, err)
+		}
+		tm, err := ParseTime("now-1d", now)
+		if err != nil || !tm.Equal(now.Add(-24*time.Hour)) {
+			t.Fatal("wrong time", tm, err)
+		}
+		if _, err = ParseTime("yesterday", now); err == nil {
+			t.Fatal("must be an error")
+		}
+	})
+}
+
+func TestNumericBounds(t *testing.T) {
+	type Bounds struct {
+		Price    float64   `json:"price" valid:"min~0.5;lt~100.25"`
+		Delta    int32     `json:"delta" valid:"gte~-10;lte~-1"`
+		Big      uint64    `json:"big" valid:"gt~18446744073709551614"`
+		Ratio    float32   `json:"ratio" valid:"gt~0;lte~1"`
+		Count    int       `json:"count" valid:"max~2.5"`
+		Negative int       `json:"negative" valid:"digit~3"`
+		Prices   []float64 `json:"prices" valid:"min~-0.5"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		b := Bounds{Price: 0.5, Delta: -1, Big: math.MaxUint64, Ratio: 1, Count: 2, Negative: -123, Prices: []float64{-0.5, 1}}
+		if e := ValidateStruct(b); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		b := Bounds{Price: 100.25, Delta: -11, Big: 1, Ratio: 0, Count: 3, Negative: -12, Prices: []float64{-0.6}}
+		e := ValidateStruct(b)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{"price", "delta", "big", "ratio", "count", "negative", "prices"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+		if e = ValidateStruct(struct {
+			Value float64 `valid:"min~0"`
+		}{Value: math.NaN()}); e == nil {
+			t.Fatal("NaN must be invalid")
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		for _, s := range []interface{}{
+			struct {
+				Value int8 `valid:"min~300"`
+			}{},
+			struct {
+				Value uint `valid:"max~-1"`
+			}{},
+			struct {
+				Value []uint16 `valid:"lt~70000"`
+			}{},
+			struct {
+				Value float32 `valid:"gt~1e40"`
+			}{},
+			struct {
+				Value int `valid:"min~abc"`
+			}{},
+			struct {
+				Value string `valid:"max~-3"`
+			}{},
+			struct {
+				Value map[int8]string `valid:"dive~keys;max~1000"`
+			}{},
+			struct {
+				Value time.Time `valid:"before~tomorrow"`
+			}{},
+			struct {
+				Value int `valid:"required|min~1.5e400"`
+			}{},
+		} {
+			if e := CompileStruct(s); e == nil {
+				t.Fatalf("compile error expected for %T", s)
+			} else {
+				t.Log(e.Error())
+			}
+		}
+	})
+}
+
+func TestRangeNotation(t *testing.T) {
+	type Ranges struct {
+		Percent  float64       `json:"percent" valid:"range~[0,100)"`
+		Positive float32       `json:"positive" valid:"range~(0,]"`
+		Limit    int           `json:"limit" valid:"range~:100"`
+		Negative int64         `json:"negative" valid:"range~-10:-1"`
+		Union    uint8         `json:"union" valid:"range~1:5,10:20,(30,40]"`
+		Letter   string        `json:"letter" valid:"range~a:m"`
+		Timeout  time.Duration `json:"timeout" valid:"range~(0s,1h]"`
+		Created  time.Time     `json:"created" valid:"range~[2000-01-01T00:00:00Z,2030-01-01T00:00:00Z)"`
+		Items    []int         `json:"items" valid:"range~0:"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		r := Ranges{Percent: 0, Positive: 0.1, Limit: -1000, Negative: -10, Union: 40, Letter: "go",
+			Timeout: time.Hour, Created: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Items: []int{0, 100}}
+		if e := ValidateStruct(r); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		r := Ranges{Percent: 100, Positive: 0, Limit: 101, Negative: 0, Union: 30, Letter: "rust",
+			Timeout: 0, Created: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Items: []int{-1}}
+		e := ValidateStruct(r)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := []string{"percent", "positive", "limit", "negative", "union", "letter", "timeout", "created", "items"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong error names", names)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		for _, s := range []interface{}{
+			struct {
+				Value uint8 `valid:"range~0:300"`
+			}{},
+			struct {
+				Value int `valid:"range~5"`
+			}{},
+			struct {
+				Value int `valid:"range~[1,2,3]"`
+			}{},
+			struct {
+				Value time.Time `valid:"range~[yesterday,now]"`
+			}{},
+		} {
+			if e := CompileStruct(s); e == nil {
+				t.Fatalf("compile error expected for %T", s)
+			}
+		}
+	})
+}
+
+type Money string
+
+func TestNumericConstraints(t *testing.T) {
+	type Measure struct {
+		Price    float64 `json:"price" valid:"positive;multipleOf~0.01;scale~2"`
+		Amount   Money   `json:"amount" valid:"finite;precision~6,2;multipleOf~0.05"`
+		Delta    int     `json:"delta" valid:"nonzero;negative"`
+		Weight   float32 `json:"weight" valid:"finite;positive"`
+		Quantity uint    `json:"quantity" valid:"multipleOf~5"`
+		Exact    string  `json:"exact" valid:"multipleOf~0.1"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		m := Measure{Price: 19.99, Amount: "9999.95", Delta: -3, Weight: 0.1, Quantity: 15, Exact: "1000000000000000000000.3"}
+		if e := ValidateStruct(m); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		m := Measure{Price: 0.001, Amount: "100000.53", Delta: 0, Weight: float32(math.Inf(1)), Quantity: 7, Exact: "1000000000000000000000.31"}
+		e := ValidateStruct(m)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name+":"+strings.Fields(detail.Error())[3])
+		}
+		expected := []string{"price:multipleOf", "price:scale", "amount:precision", "amount:multipleOf", "delta:nonzero",
+			"delta:negative", "weight:finite", "quantity:multipleOf", "exact:multipleOf"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong errors", names)
+		}
+		if e = ValidateStruct(Measure{Price: math.NaN(), Amount: "1e", Delta: -1, Weight: 1}); e == nil || len(e.GetDetails()) != 6 {
+			t.Fatal("NaN and invalid numeric string must be invalid", e)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		if e := CompileStruct(struct {
+			Value float64 `valid:"multipleOf~0"`
+		}{}); e == nil {
+			t.Fatal("zero multiple must be an error")
+		}
+		if e := CompileStruct(struct {
+			Value float64 `valid:"precision~2,3"`
+		}{}); e == nil {
+			t.Fatal("scale > precision must be an error")
+		}
+	})
+}
+
+type Decimal string
+
+func (d Decimal) NumericString() string {
+	return string(d)
+}
+
+type Cents struct {
+	Value int64
+}
+
+func (c *Cents) NumericString() string {
+	return strconv.FormatFloat(float64(c.Value)/100, 'f', 2, 64)
+}
+
+func TestBigNumbers(t *testing.T) {
+	type Amounts struct {
+		Int      *big.Int   `json:"int" valid:"min~-100;lt~100000000000000000000000000000;enum~1,18446744073709551616"`
+		Float    *big.Float `json:"float" valid:"range~(0,1];multipleOf~0.25"`
+		Rat      big.Rat    `json:"rat" valid:"gt~0.333;lt~0.334;positive"`
+		Decimal  Decimal    `json:"decimal" valid:"min~10.5;max~20;scale~2"`
+		Cents    Cents      `json:"cents" valid:"range~0:1000;multipleOf~0.05"`
+		Negative *big.Int   `json:"negative" valid:"negative"`
+	}
+	huge, _ := new(big.Int).SetString("18446744073709551616", 10)
+	t.Run("valid", func(t *testing.T) {
+		a := Amounts{
+			Int:      huge,
+			Float:    big.NewFloat(0.75),
+			Rat:      *big.NewRat(1, 3),
+			Decimal:  "10.50",
+			Cents:    Cents{Value: 1005},
+			Negative: big.NewInt(-1),
+		}
+		if e := ValidateStruct(&a); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		a := Amounts{
+			Int:      new(big.Int).Add(huge, big.NewInt(1)),
+			Float:    big.NewFloat(0.8),
+			Rat:      *big.NewRat(1, 2),
+			Decimal:  "9.999",
+			Cents:    Cents{Value: -1},
+			Negative: big.NewInt(0),
+		}
+		e := ValidateStruct(&a)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name+":"+strings.Fields(detail.Error())[3])
+		}
+		expected := []string{"int:enum", "float:multipleOf", "rat:lt", "decimal:min", "decimal:scale",
+			"cents:range", "cents:multipleOf", "negative:negative"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong errors", names)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		if e := CompileStruct(struct {
+			Value *big.Int `valid:"min~abc"`
+		}{}); e == nil {
+			t.Fatal("invalid bound must be an error")
+		}
+	})
+}
+
+type Status string
+
+func (s Status) Value() (driver.Value, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return strings.ToUpper(string(s)), nil
+}
+
+func TestSqlNullTypes(t *testing.T) {
+	type Row struct {
+		Name    sql.NullString   `json:"name" valid:"required;min~3;rx~^[a-z]+$"`
+		Age     sql.NullInt64    `json:"age" valid:"range~18:99"`
+		Score   *sql.NullInt32   `json:"score" valid:"notnull;max~10"`
+		Created sql.NullTime     `json:"created" valid:"past"`
+		Rate    sql.NullFloat64  `json:"rate" valid:"positive"`
+		Status  Status           `json:"status" valid:"required;enum~NEW,DONE"`
+		Tags    []sql.NullString `json:"tags" valid:"dive;max~2"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		r := Row{
+			Name:    sql.NullString{String: "john", Valid: true},
+			Age:     sql.NullInt64{Int64: 0, Valid: false},
+			Score:   &sql.NullInt32{Int32: 5, Valid: true},
+			Created: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+			Status:  "new",
+			Tags:    []sql.NullString{{String: "ab", Valid: true}, {String: "long", Valid: false}},
+		}
+		if e := ValidateStruct(r); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		r := Row{
+			Name:    sql.NullString{String: "", Valid: true},
+			Age:     sql.NullInt64{Int64: 10, Valid: true},
+			Score:   &sql.NullInt32{Int32: 11, Valid: true},
+			Created: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			Rate:    sql.NullFloat64{Float64: -1, Valid: true},
+			Status:  "",
+			Tags:    []sql.NullString{{String: "long", Valid: true}},
+		}
+		e := ValidateStruct(r)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name+":"+strings.Fields(detail.Error())[3])
+		}
+		expected := []string{"name:required", "name:min", "name:rx", "age:range", "score:max", "created:past",
+			"rate:positive", "status:required", "tags[0]:max"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Fatal("wrong errors", names)
+		}
+	})
+	t.Run("null", func(t *testing.T) {
+		e := ValidateStruct(Row{Status: "done"})
+		if e == nil || len(e.GetDetails()) != 2 {
+			t.Fatal("required and notnull errors expected", e)
+		}
+	})
+	t.Run("valuer", func(t *testing.T) {
+		defer PrepareActualValidationRules(nil)
+		PrepareActualValidationRules(map[string]ValidationCallback{
+			"rowId": func(val reflect.Value, args ...string) bool {
+				id, ok := val.Interface().(RowId)
+				return ok && id[0] != 0xff
+			},
+			"nullName": func(val reflect.Value, args ...string) bool {
+				_, ok := val.Interface().(sql.NullString)
+				return ok
+			},
+		})
+		type Entity struct {
+			Id   RowId          `json:"id" valid:"required;rowId"`
+			Name sql.NullString `json:"name" valid:"nullName;min~3|nullName"`
+		}
+		e := ValidateStruct(Entity{})
+		if e == nil || len(e.GetDetails()) != 1 || !strings.Contains(e.GetDetails()[0].Error(), "required rule on field: id") {
+			t.Fatal("zero valuer must not be set", e)
+		}
+		if e = ValidateStruct(Entity{Id: RowId{0xff}}); e == nil || !strings.Contains(e.GetDetails()[0].Error(), "rowId") {
+			t.Fatal("custom rule must get original value", e)
+		}
+		if e = ValidateStruct(Entity{Id: RowId{1}}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+}
+
+type RowId [16]byte
+
+func (id RowId) Value() (driver.Value, error) {
+	return hex.EncodeToString(id[:]), nil
+}
+
+type OrderState int
+
+func (s OrderState) String() string {
+	switch s {
+	case 1:
+		return "new"
+	case 2:
+		return "shipped"
+	}
+	return "unknown"
+}
+
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])), nil
+}
+
+func TestTextValues(t *testing.T) {
+	type Order struct {
+		State  OrderState   `json:"state" valid:"enum~new,shipped"`
+		Prev   *OrderState  `json:"prev" valid:"max~5"`
+		ID     UUID         `json:"id" valid:"rx~^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"`
+		States []OrderState `json:"states" valid:"min~3"`
+		Timer  time.Time    `json:"timer" valid:"past"`
+	}
+	prev, state := OrderState(1), OrderState(2)
+	o := Order{State: 1, Prev: &prev, ID: UUID{1, 2}, States: []OrderState{1, 2}, Timer: time.Now().Add(-time.Hour)}
+	if e := ValidateStruct(o); e == nil {
+		t.Fatal("underlying kind must be checked by default")
+	}
+	UseTextValues = true
+	defer func() { UseTextValues = false }()
+	if e := ValidateStruct(o); e != nil {
+		t.Fatal(e.GetDetails())
+	}
+	o = Order{State: 3, Prev: &state, States: []OrderState{1, 5}}
+	e := ValidateStruct(o)
+	if e == nil {
+		t.Fatal("must be an error")
+	}
+	var names []string
+	for _, detail := range e.GetDetails() {
+		names = append(names, detail.Origin().Name)
+	}
+	if strings.Join(names, " ") != "state prev" {
+		t.Fatal("wrong errors", names)
+	}
+}
+
+type PaymentMethod string
+
+func (PaymentMethod) Values() []PaymentMethod {
+	return []PaymentMethod{"card", "cash"}
+}
+
+func TestEnumSources(t *testing.T) {
+	if e := RegisterEnum("OrderStatus", "draft", "paid", "shipped"); e != nil {
+		t.Fatal(e)
+	}
+	if e := RegisterEnum("Priority", 1, 2, 3); e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterEnum("OrderStatus")
+	defer UnregisterEnum("Priority")
+	if e := RegisterEnum[string]("bad,name", "a"); e == nil {
+		t.Fatal("must be an error")
+	}
+	type Order struct {
+		Status   string          `json:"status" valid:"enum~@OrderStatus"`
+		Priority []int           `json:"priority" valid:"enum~@Priority"`
+		Method   PaymentMethod   `json:"method" valid:"enum"`
+		Methods  []PaymentMethod `json:"methods" valid:"enum"`
+		Currency string          `json:"currency" valid:"ienum~USD,EUR"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		o := Order{Status: "paid", Priority: []int{1, 3}, Method: "cash", Methods: []PaymentMethod{"card"}, Currency: "usd"}
+		if e := ValidateStruct(o); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		o := Order{Status: "Paid", Priority: []int{4}, Method: "wire", Methods: []PaymentMethod{"card", "bank"}, Currency: "rub"}
+		e := ValidateStruct(o)
+		if e == nil || len(e.GetDetails()) != 5 {
+			t.Fatal("5 errors expected", e)
+		}
+		details := e.GetDetails()
+		if details[0].Error() != "Invalid validation for enum rule on field: status. Allowed values: draft, paid, shipped" {
+			t.Fatal("wrong message", details[0].Error())
+		}
+		if !strings.HasSuffix(details[1].Error(), "Allowed values: 1, 2, 3") {
+			t.Fatal("wrong message", details[1].Error())
+		}
+		if !strings.HasSuffix(details[3].Error(), "methods. Allowed values: card, cash") {
+			t.Fatal("wrong message", details[3].Error())
+		}
+		if !strings.HasSuffix(details[4].Error(), "ienum rule on field: currency. Allowed values: USD, EUR") {
+			t.Fatal("wrong message", details[4].Error())
+		}
+	})
+	t.Run("unregistered", func(t *testing.T) {
+		type Unknown struct {
+			Status string `json:"status" valid:"enum~@Unknown"`
+		}
+		e := ValidateStruct(Unknown{Status: "a"})
+		if e == nil || e.GetHTTP() != 500 || !strings.Contains(e.Error(), "Enum @Unknown is not registered") {
+			t.Fatal("compile error expected", e)
+		}
+	})
+}
+
+func TestStateTransitions(t *testing.T) {
+	if e := RegisterTransitions("OrderStatus", map[string][]string{"draft": {"paid"}, "paid": {"shipped", "draft"}}); e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterTransitions("OrderStatus")
+	type Item struct {
+		State OrderState `json:"state" valid:"transition~1>2"`
+	}
+	type Order struct {
+		Status string `json:"status" valid:"required;transition~@OrderStatus"`
+		Items  []Item `json:"items"`
+	}
+	t.Run("allowed", func(t *testing.T) {
+		old := Order{Status: "paid", Items: []Item{{State: 1}}}
+		if e := ValidateUpdate(old, Order{Status: "shipped", Items: []Item{{State: 2}, {State: 1}}}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateUpdate(&old, &Order{Status: "paid", Items: []Item{{State: 1}}}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateUpdate(nil, &Order{Status: "shipped"}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateStruct(Order{Status: "draft"}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("rejected", func(t *testing.T) {
+		old := Order{Status: "shipped", Items: []Item{{State: 2}}}
+		e := ValidateUpdate(old, Order{Status: "draft", Items: []Item{{State: 1}}})
+		if e == nil || len(e.GetDetails()) != 2 {
+			t.Fatal("2 errors expected", e)
+		}
+		details := e.GetDetails()
+		if details[0].Error() != "Invalid validation for transition rule on field: status. Cannot change from shipped to draft" {
+			t.Fatal("wrong message", details[0].Error())
+		}
+		if details[1].Error() != "Invalid validation for transition rule on field: items[0].state. Cannot change from 2 to 1" {
+			t.Fatal("wrong message", details[1].Error())
+		}
+	})
+	t.Run("type", func(t *testing.T) {
+		e := ValidateUpdate(Item{}, Order{})
+		if e == nil || e.GetDetails()[0].Origin().Name != "type" {
+			t.Fatal("type error expected", e)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		type Negated struct {
+			Status string `valid:"!transition~a>b"`
+		}
+		type Alternative struct {
+			Status string `valid:"transition~a>b|required"`
+		}
+		type Unknown struct {
+			Status string `valid:"transition~@Unknown"`
+		}
+		for _, v := range []interface{}{Negated{}, Alternative{}, Unknown{}} {
+			if e := CompileStruct(v); e == nil {
+				t.Fatal("compile error expected", reflect.TypeOf(v))
+			}
+		}
+	})
+}
+
+func TestValidateUpdate(t *testing.T) {
+	type Profile struct {
+		Email    string `json:"email" valid:"writeOnce"`
+		Verified bool   `json:"verified"`
+	}
+	type Account struct {
+		ID        int            `json:"id" valid:"immutable"`
+		TenantID  *string        `json:"tenantId" valid:"immutable"`
+		CreatedAt time.Time      `json:"createdAt" valid:"required;immutable"`
+		Version   int            `json:"version" valid:"monotonic"`
+		Balance   Decimal        `json:"balance" valid:"monotonic"`
+		Seen      *time.Time     `json:"seen" valid:"monotonic"`
+		Profile   *Profile       `json:"profile"`
+		Limits    map[string]int `json:"limits" valid:"dive;monotonic"`
+	}
+	tenant, other := "a", "b"
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	seen := created.Add(time.Hour)
+	old := Account{ID: 1, TenantID: &tenant, CreatedAt: created, Version: 2, Balance: "10.5", Seen: &seen,
+		Profile: &Profile{}, Limits: map[string]int{"api": 10}}
+	t.Run("valid", func(t *testing.T) {
+		later := seen.Add(time.Hour)
+		same := "a"
+		v := Account{ID: 1, TenantID: &same, CreatedAt: created.In(time.Local), Version: 3, Balance: "10.50", Seen: &later,
+			Profile: &Profile{Email: "a@b.c"}, Limits: map[string]int{"api": 10, "web": 1}}
+		if e := ValidateUpdate(old, v); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateUpdate(nil, Account{ID: 2, CreatedAt: created}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		old := old
+		old.Profile = &Profile{Email: "a@b.c"}
+		v := Account{ID: 2, TenantID: &other, CreatedAt: created.Add(time.Second), Version: 1, Balance: "9.99",
+			Profile: &Profile{Email: "c@b.a"}, Limits: map[string]int{"api": 5}}
+		e := ValidateUpdate(&old, &v)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := "id tenantId createdAt version balance seen profile.email limits[\"api\"]"
+		if strings.Join(names, " ") != expected {
+			t.Fatal("wrong errors", names)
+		}
+		if e.GetDetails()[0].Error() != "Invalid validation for immutable rule on field: id. Value could not be changed" {
+			t.Fatal("wrong message", e.GetDetails()[0].Error())
+		}
+	})
+}
+
+func TestGroupConstraints(t *testing.T) {
+	type Payment struct {
+		CardToken   string   `json:"cardToken" valid:"oneOf~payment;max~8"`
+		BankAccount *string  `json:"bankAccount" valid:"oneOf~payment"`
+		Wallet      []string `json:"wallet" valid:"oneOf~payment"`
+		Email       string   `json:"email" valid:"anyOf~contact"`
+		Phone       string   `json:"phone"`
+		Coupon      string   `json:"coupon" valid:"exclusive~discount"`
+		Promo       string   `json:"promo" valid:"exclusive~discount"`
+	}
+	type Order struct {
+		Payment Payment `json:"payment"`
+	}
+	if e := RegisterGroup(Payment{}, "anyOf", "contact", "Phone"); e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterGroups(Payment{})
+	if e := RegisterGroup(Payment{}, "someOf", "contact", "Phone"); e == nil {
+		t.Fatal("unknown group rule must be an error")
+	}
+	if e := RegisterGroup(Payment{}, "anyOf", "contact", "Fax"); e == nil {
+		t.Fatal("unknown field must be an error")
+	}
+	if e := RegisterGroup(Payment{}, "anyOf", "con,tact", "Phone"); e == nil {
+		t.Fatal("invalid group name must be an error")
+	}
+	t.Run("valid", func(t *testing.T) {
+		account := ""
+		if e := ValidateStruct(Payment{BankAccount: &account, Phone: "123", Coupon: "a"}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := ValidateStruct(Payment{CardToken: "12345678", Email: "a@b.c"}); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		e := ValidateStruct(Order{Payment: Payment{CardToken: "123456789", Wallet: []string{"w"}, Coupon: "a", Promo: "b"}})
+		if e == nil || len(e.GetDetails()) != 4 {
+			t.Fatal("4 errors expected", e)
+		}
+		details := e.GetDetails()
+		expected := []string{
+			"Invalid validation for max rule on field: payment.cardToken",
+			"Invalid validation for oneOf rule on group: payment.payment. Exactly one of cardToken, bankAccount, wallet must be set",
+			"Invalid validation for anyOf rule on group: payment.contact. At least one of email, phone must be set",
+			"Invalid validation for exclusive rule on group: payment.discount. Only one of coupon, promo could be set",
+		}
+		for i := range expected {
+			if details[i].Error() != expected[i] {
+				t.Fatal("wrong message", details[i].Error())
+			}
+		}
+		if e := ValidateStruct(Payment{Email: "a@b.c"}); e == nil || e.GetDetails()[0].Origin().Name != "payment" {
+			t.Fatal("oneOf error expected", e)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		type Mixed struct {
+			A string `valid:"oneOf~g"`
+			B string `valid:"anyOf~g"`
+		}
+		type Alternative struct {
+			A string `valid:"oneOf~g|required"`
+		}
+		type Unnamed struct {
+			A string `valid:"oneOf"`
+		}
+		for _, v := range []interface{}{Mixed{}, Alternative{}, Unnamed{}} {
+			if e := CompileStruct(v); e == nil {
+				t.Fatal("compile error expected", reflect.TypeOf(v))
+			}
+		}
+	})
+}
+
+func TestDiscriminatedUnion(t *testing.T) {
+	type Created struct {
+		ID   int    `json:"id" valid:"required"`
+		Name string `json:"name" valid:"required;min~3"`
+	}
+	type Deleted struct {
+		ID     int    `json:"id" valid:"required"`
+		Reason string `json:"reason" valid:"enum~spam,user"`
+	}
+	if e := RegisterUnion("event", "type", map[string]interface{}{"created": Created{}, "deleted": &Deleted{}}); e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterUnion("event")
+	if e := RegisterUnion("bad", "type", map[string]interface{}{"a": 1}); e == nil {
+		t.Fatal("variant must be a struct")
+	}
+	if e := RegisterUnion("bad,name", "type", map[string]interface{}{"created": Created{}}); e == nil {
+		t.Fatal("comma must not be in name")
+	}
+	type Event struct {
+		Type string      `json:"type" valid:"required"`
+		Data interface{} `json:"data" valid:"required;union~event"`
+	}
+	type RawEvent struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data" valid:"union~event"`
+	}
+	t.Run("valid", func(t *testing.T) {
+		events := []interface{}{
+			Event{Type: "created", Data: Created{ID: 1, Name: "john"}},
+			Event{Type: "deleted", Data: &Deleted{ID: 1, Reason: "spam"}},
+			Event{Type: "created", Data: map[string]interface{}{"id": 1, "name": "john"}},
+			RawEvent{Type: "deleted", Data: json.RawMessage(`{"id":1,"reason":"user"}`)},
+			RawEvent{Type: "unknown", Data: json.RawMessage(`null`)},
+		}
+		for _, event := range events {
+			if e := ValidateStruct(event); e != nil {
+				t.Fatal(e.GetDetails())
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var names []string
+		events := []interface{}{
+			Event{Type: "created", Data: Created{Name: "jo"}},
+			Event{Type: "created", Data: map[string]interface{}{"id": 1}},
+			RawEvent{Type: "deleted", Data: json.RawMessage(`{"id":1,"reason":"bot"}`)},
+			RawEvent{Type: "deleted", Data: json.RawMessage(`{"id":"1"}`)},
+			Event{Type: "created", Data: Deleted{ID: 1}},
+			Event{Type: "updated", Data: Created{ID: 1, Name: "john"}},
+		}
+		for _, event := range events {
+			e := ValidateStruct(event)
+			if e == nil {
+				t.Fatal("must be an error", event)
+			}
+			for _, detail := range e.GetDetails() {
+				names = append(names, detail.Origin().Name)
+			}
+		}
+		expected := "data.id data.name data.name data.name data.reason data data type"
+		if strings.Join(names, " ") != expected {
+			t.Fatal("wrong errors", names)
+		}
+		e := ValidateStruct(Event{Type: "updated", Data: Created{}})
+		if e.GetDetails()[0].Error() != "Invalid validation for union rule on field: type. Unknown variant 'updated'. Allowed variants: created, deleted" {
+			t.Fatal("wrong message", e.GetDetails()[0].Error())
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		type NoDiscriminator struct {
+			Data interface{} `json:"data" valid:"union~event"`
+		}
+		type Unknown struct {
+			Type string      `json:"type"`
+			Data interface{} `json:"data" valid:"union~unknown"`
+		}
+		for _, v := range []interface{}{NoDiscriminator{}, Unknown{}} {
+			if e := CompileStruct(v); e == nil {
+				t.Fatal("compile error expected", reflect.TypeOf(v))
+			}
+		}
+	})
+}
+
+func TestVar(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		limit := 10
+		if e := Var(limit, "range~1:100"); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := Var(&limit, "required;min~1", "limit"); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := Var([]string{"a", "b"}, "unique;dive;enum~a,b,c"); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if e := Var(nil, "min~1"); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		e := Var(200, "range~1:100", "limit")
+		if e == nil || len(e.GetDetails()) != 1 {
+			t.Fatal("error expected", e)
+		}
+		if e.GetDetails()[0].Error() != "Invalid validation for range rule on field: limit" {
+			t.Fatal("wrong message", e.GetDetails()[0].Error())
+		}
+		if e = Var(nil, "required"); e == nil || e.GetDetails()[0].Origin().Name != "value" {
+			t.Fatal("required error expected", e)
+		}
+		e = Vars(
+			Variable{Name: "limit", Value: 0, Rules: "range~1:100"},
+			Variable{Name: "sort", Value: "up", Rules: "enum~asc,desc"},
+			Variable{Name: "tags", Value: []string{"a", "abc"}, Rules: "dive;max~2"},
+			Variable{Name: "address", Value: Address{Zip: "1", City: "a"}},
+		)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		if strings.Join(names, " ") != "limit sort tags[1] address.zip" {
+			t.Fatal("wrong errors", names)
+		}
+	})
+	t.Run("compile", func(t *testing.T) {
+		e := Var("a", "min~(1")
+		if e == nil || e.GetHTTP() != 500 {
+			t.Fatal("compile error expected", e)
+		}
+		if e = Var(1, "dive;min~1"); e == nil || e.GetHTTP() != 500 {
+			t.Fatal("compile error expected", e)
+		}
+	})
+	t.Run("cache_limit", func(t *testing.T) {
+		defer func(max int) { MaxVarPlans = max }(MaxVarPlans)
+		resetPlans()
+		MaxVarPlans = 2
+		for i := 0; i < 5; i++ {
+			if e := Var(i, "max~"+strconv.Itoa(i)); e != nil {
+				t.Fatal(e.GetDetails())
+			}
+		}
+		if e := Var(5, "max~4"); e == nil {
+			t.Fatal("must be an error")
+		}
+		if varPlansCount != 2 {
+			t.Fatal("cache must be limited", varPlansCount)
+		}
+		resetPlans()
+		if varPlansCount != 0 {
+			t.Fatal("cache must be empty", varPlansCount)
+		}
+	})
+}
+
+func TestSchemaBuilder(t *testing.T) {
+	type User struct {
+		Name    string            `json:"name" valid:"required"`
+		Age     int               `json:"age"`
+		Tags    []string          `json:"tags"`
+		Email   string            `json:"email"`
+		Attrs   map[string]string `json:"attrs"`
+		Timeout time.Duration     `json:"timeout"`
+	}
+	define := func() *SchemaBuilder[User] {
+		s := Schema[User]()
+		Field(s, func(u *User) *string { return &u.Name }).MinLen(3).Rx("^[a-z;|]+$")
+		Field(s, func(u *User) *int { return &u.Age }).Min(18).Max(99)
+		Dive(Field(s, func(u *User) *[]string { return &u.Tags }).MaxItems(2).Unique()).Enum("a", "b", "c")
+		Field(s, func(u *User) *string { return &u.Email }).Not("enum", "root@example.com")
+		DiveKeys(Field(s, func(u *User) *map[string]string { return &u.Attrs })).Len(2)
+		DiveValues(Field(s, func(u *User) *map[string]string { return &u.Attrs })).Enum("", "x")
+		Field(s, func(u *User) *time.Duration { return &u.Timeout }).Gt(time.Second).Lt(time.Minute)
+		if e := s.Compile(); e != nil {
+			t.Fatal(e)
+		}
+		return s
+	}
+	define()
+	s := define()
+	defer s.Reset()
+	t.Run("valid", func(t *testing.T) {
+		u := User{Name: "jo;|n", Age: 20, Tags: []string{"a", "b"}, Email: "a@b.c", Attrs: map[string]string{"ab": ""}, Timeout: 2 * time.Second}
+		if e := s.Validate(&u); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		u := User{Name: "Jo", Age: 10, Tags: []string{"a", "a", "d"}, Email: "root@example.com", Attrs: map[string]string{"abc": "y"}, Timeout: time.Second}
+		e := ValidateStruct(u)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name+":"+strings.Fields(detail.Error())[3])
+		}
+		expected := `name:min name:rx age:min tags:maxItems tags:unique tags[2]:enum email:!enum attrs["abc"]:len attrs["abc"]:enum timeout:gt`
+		if strings.Join(names, " ") != expected {
+			t.Fatal("wrong errors", names)
+		}
+		if e = ValidateStruct(User{}); e == nil || e.GetDetails()[0].Error() != "Invalid validation for required rule on field: name" {
+			t.Fatal("tag rules must be applied", e)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		type Other struct {
+			Code string
+		}
+		other := Schema[Other]()
+		defer other.Reset()
+		Field(other, func(o *Other) *string { code := o.Code; return &code }).Required()
+		if e := other.Compile(); e == nil {
+			t.Fatal("selector error expected")
+		}
+		bound := Schema[Other]()
+		defer bound.Reset()
+		Field(bound, func(o *Other) *string { return &o.Code }).Required().Min("abc")
+		if e := bound.Compile(); e == nil {
+			t.Fatal("argument error expected")
+		}
+		if e := ValidateStruct(Other{}); e != nil {
+			t.Fatal("invalid schema must not be registered", e)
+		}
+		invalid := Schema[int]()
+		defer invalid.Reset()
+		if e := invalid.Compile(); e == nil {
+			t.Fatal("struct type error expected")
+		}
+		changed := Schema[Other]()
+		defer changed.Reset()
+		Field(changed, func(o *Other) *string { return &o.Code }).Required()
+		if e := changed.Validate(&Other{}); e == nil || e.GetHTTP() != 500 {
+			t.Fatal("not compiled error expected", e)
+		}
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		type Item struct {
+			Code string
+		}
+		item := Schema[Item]()
+		defer item.Reset()
+		Field(item, func(i *Item) *string { return &i.Code }).Required()
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = item.Compile()
+		}()
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = ValidateStruct(Item{})
+			}()
+		}
+		wg.Wait()
+		if e := ValidateStruct(Item{}); e == nil {
+			t.Fatal("schema rules must be applied")
+		}
+	})
+}
+
+func TestValidateDocument(t *testing.T) {
+	schema := DocumentSchema{
+		"name":           "required;min~3",
+		"age":            "range~18:99",
+		"address":        "required",
+		"address.zip":    "required;digit~5",
+		"items":          "minItems~1",
+		"items[].qty":    "required;min~1",
+		"items[].tags":   "dive;len~2",
+		"matrix[][]":     "enum~0,1",
+		"options.debug":  "notnull",
+		"options.labels": "maxItems~2;dive~keys;rx~^[a-z]+$",
+	}
+	t.Run("valid", func(t *testing.T) {
+		data := json.RawMessage(`{"name":"john","age":20,"address":{"zip":"12345"},"items":[{"qty":1,"tags":["ab"]}],
+			"matrix":[[0,1],[1]],"options":{"debug":false,"labels":{"env":"prod"}}}`)
+		if e := ValidateJSON(data, schema); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		document := map[string]interface{}{"name": "john", "address": map[string]interface{}{"zip": "12345"}}
+		if e := ValidateMap(document, schema); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		data := json.RawMessage(`{"name":"jo","age":10,"address":"street","items":[{"qty":0,"tags":["abc"]},{}],
+			"matrix":[[2]],"options":{"labels":{"ENV":"prod","a":"","b":""}}}`)
+		e := ValidateJSON(data, schema)
+		if e == nil {
+			t.Fatal("must be an error")
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := `address age items[0].qty items[0].qty items[0].tags[0] items[1].qty matrix[0][0] name options.debug options.labels options.labels["ENV"]`
+		if strings.Join(names, " ") != expected {
+			t.Fatal("wrong errors", names)
+		}
+		if e.GetDetails()[0].Error() != "Invalid type of field: address. Object required" {
+			t.Fatal("wrong message", e.GetDetails()[0].Error())
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		if e := ValidateJSON(json.RawMessage(`{"name":`), schema); e == nil || e.GetDetails()[0].Origin().Name != "body" {
+			t.Fatal("json error expected", e)
+		}
+		if e := ValidateJSON(json.RawMessage(`[1]`), schema); e == nil || e.GetDetails()[0].Origin().Name != "type" {
+			t.Fatal("type error expected", e)
+		}
+		if e := ValidateMap(map[string]interface{}{}, DocumentSchema{"a..b": "required"}); e == nil || e.GetHTTP() != 500 {
+			t.Fatal("compile error expected", e)
+		}
+		if e := ValidateMap(map[string]interface{}{}, DocumentSchema{"a": "dive;min~1"}); e != nil {
+			t.Fatal(e)
+		}
+	})
+}
+
+type DecodeItem struct {
+	Sku string `json:"sku" valid:"required"`
+	Qty int8   `json:"qty" valid:"required;min~1"`
+}
+
+type DecodeOrder struct {
+	Name    string       `json:"name" valid:"required;min~3"`
+	Items   []DecodeItem `json:"items" valid:"minItems~1"`
+	Created time.Time    `json:"created"`
+	Count   int          `json:"count,string"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var order DecodeOrder
+		body := `{"name":"john","items":[{"sku":"a","qty":2}],"created":"2024-01-02T00:00:00Z","count":"3","extra":1}`
+		if e := DecodeJSON(strings.NewReader(body), &order); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if order.Items[0].Qty != 2 || order.Count != 3 || order.Created.Year() != 2024 {
+			t.Fatal("wrong decoded value", order)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var order DecodeOrder
+		body := `{"name":"jo","items":[{"sku":"a","qty":1},{"qty":0},{"sku":"c","qty":"x"},{"sku":"d","qty":300}],"created":"now","count":"x","extra":1}`
+		e := DecodeStrictJSON(strings.NewReader(body), &order)
+		if e == nil || e.GetHTTP() != 400 {
+			t.Fatal("must be a validation error", e)
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		expected := `count created extra items[2].qty items[3].qty name items[1].sku items[1].qty items[1].qty`
+		if strings.Join(names, " ") != expected {
+			t.Fatal("wrong errors", names)
+		}
+		if e.GetDetails()[3].Error() != "Invalid type of field: items[2].qty. Number required" {
+			t.Fatal("wrong message", e.GetDetails()[3].Error())
+		}
+		if e.GetDetails()[4].Error() != "Invalid value of field: items[3].qty. Number is out of range of type int8" {
+			t.Fatal("wrong message", e.GetDetails()[4].Error())
+		}
+		if e = DecodeJSON(strings.NewReader(`{"extra":1,"name":"john","items":[{"sku":"a","qty":1}]}`), &DecodeOrder{}); e != nil {
+			t.Fatal("unknown fields are allowed", e.GetDetails())
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		if e := DecodeJSON(strings.NewReader(`{"name":`), &DecodeOrder{}); e == nil || e.GetDetails()[0].Origin().Name != "body" {
+			t.Fatal("json error expected", e)
+		}
+		if e := DecodeJSON(strings.NewReader(`{} {}`), &DecodeOrder{}); e == nil || e.GetDetails()[0].Origin().Name != "body" {
+			t.Fatal("json error expected", e)
+		}
+		if e := DecodeJSON(strings.NewReader(`[]`), &DecodeOrder{}); e == nil || e.GetDetails()[0].Error() != "Type object required. Type array received" {
+			t.Fatal("type error expected", e)
+		}
+		if e := DecodeJSON(strings.NewReader(`{}`), DecodeOrder{}); e == nil || e.GetHTTP() != 500 {
+			t.Fatal("argument error expected", e)
+		}
+		var keyed struct {
+			M map[int]string `json:"m"`
+			S fmt.Stringer   `json:"s"`
+		}
+		e := DecodeJSON(strings.NewReader(`{"m":{"abc":"x"}}`), &keyed)
+		if e == nil || e.GetDetails()[0].Origin().Name != "body" {
+			t.Fatal("decode error expected", e)
+		}
+		e = DecodeJSON(strings.NewReader(`{"s":"x"}`), &keyed)
+		if e == nil || e.GetDetails()[0].Origin().Name != "body" {
+			t.Fatal("decode error expected", e)
+		}
+	})
+}
+
+type BindSearch struct {
+	Query   string                  `json:"query" form:"q" valid:"required;min~2"`
+	Page    int                     `json:"page" valid:"min~1"`
+	Tags    []string                `json:"tags" valid:"maxItems~2"`
+	Timeout time.Duration           `json:"timeout"`
+	Since   *time.Time              `json:"since"`
+	Name    string                  `json:"name"`
+	File    *multipart.FileHeader   `json:"-" form:"file"`
+	Files   []*multipart.FileHeader `json:"-" form:"files"`
+}
+
+func TestBind(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		var search BindSearch
+		r := httptest.NewRequest(http.MethodGet, "/?q=go&page=2&tags=a&tags=b&timeout=5s&since=2024-01-02T00:00:00Z", nil)
+		if e := Bind(r, &search); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if search.Query != "go" || search.Page != 2 || len(search.Tags) != 2 || search.Timeout != 5*time.Second || search.Since.Year() != 2024 {
+			t.Fatal("wrong bound value", search)
+		}
+		r = httptest.NewRequest(http.MethodGet, "/?q=g&page=x&tags=a&tags=b&tags=c&since=now", nil)
+		e := Bind(r, &BindSearch{})
+		if e == nil || e.GetHTTP() != 400 {
+			t.Fatal("must be a validation error", e)
+		}
+		var names []string
+		for _, detail := range e.GetDetails() {
+			names = append(names, detail.Origin().Name)
+		}
+		if strings.Join(names, " ") != "page since query tags" {
+			t.Fatal("wrong errors", names)
+		}
+	})
+	t.Run("hidden", func(t *testing.T) {
+		var user struct {
+			Name    string `json:"name"`
+			IsAdmin bool   `json:"-"`
+		}
+		r := httptest.NewRequest(http.MethodPost, "/?IsAdmin=true&isAdmin=true", strings.NewReader(`{"name":"john"}`))
+		r.Header.Set("Content-Type", "application/json")
+		if e := Bind(r, &user); e != nil || user.IsAdmin || user.Name != "john" {
+			t.Fatal("field hidden from json must not be bound", e, user)
+		}
+	})
+	t.Run("body", func(t *testing.T) {
+		var search BindSearch
+		r := httptest.NewRequest(http.MethodPost, "/?page=3", strings.NewReader(`{"query":"go","name":"john"}`))
+		r.Header.Set("Content-Type", "application/json; charset=utf-8")
+		if e := Bind(r, &search); e != nil {
+			t.Fatal(e.GetDetails())
+		}
+		if search.Query != "go" || search.Name != "john" || search.Page != 3 {
+			t.Fatal("wrong bound value", search)
+		}
+		r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"q": {"go"}, "page": {"1"}, "name": {"john"}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if e := Bind(r, &search); e != nil || search.Query != "go" || search.Name != "john" {
+			t.Fatal("wrong bound value", e, search)
+		}
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("q", "files")
+		_ = writer.WriteField("page", "1")
+		for _, name := range []string{"file", "files", "files"} {
+			part, _ := writer.CreateFormFile(name, name+".txt")
+			_, _ = part.Write([]byte("data"))
+		}
+		_ = writer.Close()
+		r = httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", writer.FormDataContentType())
+		search = BindSearch{}
+		if e := Bind(r, &search); e != nil || search.File == nil || len(search.Files) != 2 {
+			t.Fatal("wrong bound files", e, search)
+		}
+		defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+		MaxBodySize = 16
+		for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded", writer.FormDataContentType()} {
+			r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query":"`+strings.Repeat("a", 32)+`"}`))
+			r.Header.Set("Content-Type", contentType)
+			if e := Bind(r, &search); e == nil || e.GetHTTP() != http.StatusRequestEntityTooLarge {
+				t.Fatal("body size error expected", contentType, e)
+			}
+		}
+		r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("q=go"))
+		r.Header.Set("Content-Type", "text/plain")
+		if e := Bind(r, &search); e == nil || e.GetHTTP() != http.StatusUnsupportedMediaType {
+			t.Fatal("content type error expected", e)
+		}
+	})
+	t.Run("handler", func(t *testing.T) {
+		handler := Handle(func(w http.ResponseWriter, r *http.Request, search *BindSearch) {
+			_, _ = w.Write([]byte(search.Query))
+		})
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/?q=go&page=1", nil))
+		if w.Code != http.StatusOK || w.Body.String() != "go" {
+			t.Fatal("wrong response", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/?page=1", nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"name":"query"`) {
+			t.Fatal("wrong response", w.Code, w.Body.String())
+		}
+		middleware := Middleware[BindSearch](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			search, ok := Bound[BindSearch](r.Context())
+			if !ok {
+				t.Fatal("value must be bound")
+			}
+			_, _ = w.Write([]byte(search.Query))
+		}))
+		w = httptest.NewRecorder()
+		middleware.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?q=go&page=1", nil))
+		if w.Body.String() != "go" {
+			t.Fatal("wrong response", w.Body.String())
+		}
+		defer func(writer ErrorWriter) { WriteError = writer }(WriteError)
+		WriteError = func(w http.ResponseWriter, r *http.Request, e porterr.IError) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		w = httptest.NewRecorder()
+		middleware.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatal("wrong response", w.Code)
+		}
+	})
+}
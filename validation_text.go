@@ -0,0 +1,55 @@
+package v
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// UseTextValues string rules rx, enum, min and max use text form of values
+// implementing encoding.TextMarshaler or fmt.Stringer instead of underlying kind
+// Times, durations and big numbers are not affected
+var UseTextValues = false
+
+// Types of text interfaces
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// isTextType check if type or pointer to type has text form
+func isTextType(t reflect.Type) bool {
+	if t == durationType || isBigType(t) || (t.Kind() == reflect.Struct && t.ConvertibleTo(timeType)) {
+		return false
+	}
+	for _, i := range []reflect.Type{textMarshalerType, stringerType} {
+		if t.Implements(i) || reflect.PtrTo(t).Implements(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// textValue get text form of value if UseTextValues is set
+// Value is returned as is if it has no text form or text could not be marshaled
+func textValue(val reflect.Value) reflect.Value {
+	if !UseTextValues || !val.IsValid() || !val.CanInterface() || !isTextType(val.Type()) {
+		return val
+	}
+	if val.CanAddr() {
+		val = val.Addr()
+	} else {
+		val = pointerTo(val)
+	}
+	switch v := val.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return val.Elem()
+		}
+		return reflect.ValueOf(string(text))
+	case fmt.Stringer:
+		return reflect.ValueOf(v.String())
+	}
+	return val.Elem()
+}
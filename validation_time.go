@@ -0,0 +1,188 @@
+package v
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Now current time used by time rules
+// Could be replaced in tests
+var Now = time.Now
+
+// Types of time values
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Relative time prefix
+const timeNow = "now"
+
+// Date layout for time arguments
+const dateLayout = "2006-01-02"
+
+// ParseDuration parse duration in go syntax with additional days unit
+// Example: 90d, 1d12h, -30m
+func ParseDuration(s string) (time.Duration, error) {
+	i := strings.IndexByte(s, 'd')
+	if i < 0 {
+		return time.ParseDuration(s)
+	}
+	var sign time.Duration = 1
+	days := s[:i]
+	if strings.HasPrefix(days, "-") {
+		sign = -1
+		days = days[1:]
+	} else if strings.HasPrefix(days, "+") {
+		days = days[1:]
+	}
+	n, err := strconv.ParseUint(days, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	var rest time.Duration
+	if i+1 < len(s) {
+		rest, err = time.ParseDuration(s[i+1:])
+		if err != nil {
+			return 0, err
+		}
+	}
+	return sign * (time.Duration(n)*24*time.Hour + rest), nil
+}
+
+// ParseTime parse absolute or relative time argument
+// Absolute time in RFC 3339 format or date: 2000-01-01T00:00:00Z, 2000-01-01
+// Relative time is offset from now: now, now-90d, +1h
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(s, timeNow) {
+		s = s[len(timeNow):]
+		if s == "" {
+			return now, nil
+		}
+	}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	if len(s) == len(dateLayout) {
+		return time.Parse(dateLayout, s)
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// timeValue get time from value. Returns false if value is not a time
+// Time of unexported field could not be read and returned as zero time
+func timeValue(val reflect.Value) (time.Time, bool) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return time.Time{}, true
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct || !val.Type().ConvertibleTo(timeType) {
+		return time.Time{}, false
+	}
+	if !val.CanInterface() {
+		return time.Time{}, true
+	}
+	return val.Convert(timeType).Interface().(time.Time), true
+}
+
+// isTimeRuleValid check time value with comparator
+func isTimeRuleValid(val reflect.Value, args []string, compare func(t time.Time, args []time.Time) bool) bool {
+	if val.Kind() == reflect.Slice {
+		for i := 0; i < val.Len(); i++ {
+			if !isTimeRuleValid(val.Index(i), args, compare) {
+				return false
+			}
+		}
+		return true
+	}
+	t, ok := timeValue(val)
+	if !ok {
+		return false
+	}
+	if t.IsZero() {
+		return true
+	}
+	now := Now()
+	var bounds = make([]time.Time, len(args))
+	for i := range args {
+		bound, err := ParseTime(args[i], now)
+		if err != nil {
+			return false
+		}
+		bounds[i] = bound
+	}
+	return compare(t, bounds)
+}
+
+// IsBeforeValid check if time is before argument
+// Example: before~2030-01-01, before~now+24h
+func IsBeforeValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	return isTimeRuleValid(val, args[:1], func(t time.Time, bounds []time.Time) bool {
+		return t.Before(bounds[0])
+	})
+}
+
+// IsAfterValid check if time is after argument
+// Example: after~2000-01-01T00:00:00Z, after~now-90d
+func IsAfterValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	return isTimeRuleValid(val, args[:1], func(t time.Time, bounds []time.Time) bool {
+		return t.After(bounds[0])
+	})
+}
+
+// IsBetweenValid check if time is between arguments inclusive
+// Example: between~2000-01-01,now
+func IsBetweenValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	bounds := strings.Split(args[0], ",")
+	if len(bounds) != 2 {
+		return false
+	}
+	return isTimeRuleValid(val, bounds, func(t time.Time, bounds []time.Time) bool {
+		return !t.Before(bounds[0]) && !t.After(bounds[1])
+	})
+}
+
+// IsPastValid check if time is in the past
+func IsPastValid(val reflect.Value, args ...string) bool {
+	return isTimeRuleValid(val, []string{timeNow}, func(t time.Time, bounds []time.Time) bool {
+		return t.Before(bounds[0])
+	})
+}
+
+// IsFutureValid check if time is in the future
+func IsFutureValid(val reflect.Value, args ...string) bool {
+	return isTimeRuleValid(val, []string{timeNow}, func(t time.Time, bounds []time.Time) bool {
+		return t.After(bounds[0])
+	})
+}
+
+// IsMaxAgeValid check if time is not older than duration
+// Example: maxAge~90d
+func IsMaxAgeValid(val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	if _, err := ParseDuration(args[0]); err != nil {
+		return false
+	}
+	return isTimeRuleValid(val, []string{timeNow + "-" + strings.TrimPrefix(args[0], "+")}, func(t time.Time, bounds []time.Time) bool {
+		return !t.Before(bounds[0])
+	})
+}
@@ -0,0 +1,180 @@
+package v
+
+import (
+	"encoding/json"
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Union rule. Rule argument is the name of registered union
+// Example: valid:"union~event"
+const ruleUnion = "union"
+
+// union registered variants of discriminated union
+type union struct {
+	// Json name of discriminator field
+	discriminator string
+	// Discriminator value -> type of variant struct
+	variants map[string]reflect.Type
+}
+
+// unionPlan compiled union of struct field
+type unionPlan struct {
+	// Union name
+	name string
+	// Index of discriminator field in struct
+	discriminator int
+	// Name of discriminator field used in errors
+	discriminatorName string
+	// Discriminator value -> type of variant struct
+	variants map[string]reflect.Type
+}
+
+// Type of json.RawMessage
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// Registered unions. Union name -> union
+var unions = make(map[string]union)
+
+// RegisterUnion register variants of discriminated union
+// Discriminator is json name of struct field which value selects variant of the field with union rule
+// Example
+//
+//	v.RegisterUnion("event", "type", map[string]interface{}{"created": Created{}, "deleted": Deleted{}})
+//	Type string      `json:"type" valid:"required"`
+//	Data interface{} `json:"data" valid:"required;union~event"`
+func RegisterUnion[T comparable](name string, discriminator string, variants map[T]interface{}) porterr.IError {
+	if !validName(name) {
+		return porterr.New(porterr.PortErrorArgument, "Invalid union name '"+name+"'")
+	}
+	if discriminator == "" || len(variants) == 0 {
+		return porterr.New(porterr.PortErrorArgument, "Union discriminator and variants are required")
+	}
+	u := union{discriminator: discriminator, variants: make(map[string]reflect.Type, len(variants))}
+	for value, variant := range variants {
+		t := reflect.TypeOf(variant)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		key := enumString(reflect.ValueOf(value))
+		if t == nil || t.Kind() != reflect.Struct {
+			return porterr.New(porterr.PortErrorArgument, "Variant "+key+" of union "+name+" must be a struct")
+		}
+		u.variants[key] = t
+	}
+	unions[name] = u
+	resetPlans()
+	return nil
+}
+
+// UnregisterUnion remove registered union
+func UnregisterUnion(name string) {
+	delete(unions, name)
+	resetPlans()
+}
+
+// compileUnion remove union rule from field rules and resolve discriminator field of struct
+func compileUnion(rules ValidationRules, te reflect.Type) (ValidationRules, *unionPlan, porterr.IError) {
+	var plan *unionPlan
+	var fieldRules = make(ValidationRules, 0, len(rules))
+	for _, rule := range rules {
+		if containsUnion(rule.Alternatives) {
+			return nil, nil, porterr.New(porterr.PortErrorParser, "Rule "+ruleUnion+" is not allowed in alternatives, groups and aliases")
+		}
+		if rule.Name != ruleUnion {
+			fieldRules = append(fieldRules, rule)
+			continue
+		}
+		if rule.Not || plan != nil {
+			return nil, nil, porterr.New(porterr.PortErrorParser, "Rule "+rule.String()+" could not be negated or duplicated")
+		}
+		if len(rule.Args) == 0 {
+			return nil, nil, porterr.New(porterr.PortErrorParser, "Rule "+ruleUnion+" requires union name")
+		}
+		u, ok := unions[rule.Args[0]]
+		if !ok {
+			return nil, nil, porterr.New(porterr.PortErrorArgument, "Union "+rule.Args[0]+" is not registered")
+		}
+		plan = &unionPlan{name: rule.Args[0], discriminator: -1, discriminatorName: u.discriminator, variants: u.variants}
+		for i := 0; i < te.NumField(); i++ {
+			if name, _ := fieldName(te.Field(i)); name == u.discriminator {
+				plan.discriminator = i
+				break
+			}
+		}
+		if plan.discriminator < 0 {
+			return nil, nil, porterr.New(porterr.PortErrorParser, "Discriminator "+u.discriminator+" of union "+plan.name+" not found")
+		}
+	}
+	return fieldRules, plan, nil
+}
+
+// containsUnion check if union rule is used inside alternatives
+func containsUnion(alternatives []ValidationRules) bool {
+	for _, alternative := range alternatives {
+		for _, rule := range alternative {
+			if rule.Name == ruleUnion || containsUnion(rule.Alternatives) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateUnion validate value of union field by variant selected by discriminator
+// Value could be variant struct, json.RawMessage or map decoded from json
+// Unknown discriminator value is reported on discriminator field with path of the struct
+func (vl *validator) validateUnion(ve reflect.Value, f reflect.Value, old reflect.Value, path string, name string, plan *unionPlan) porterr.IError {
+	value := derefValue(f)
+	if !value.IsValid() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+		return nil
+	}
+	if value.Type() == rawMessageType && string(value.Bytes()) == "null" {
+		return nil
+	}
+	key := enumString(derefValue(ve.Field(plan.discriminator)))
+	t, ok := plan.variants[key]
+	if !ok {
+		path = fieldPath(path, plan.discriminatorName)
+		vl.e.PushDetail(porterr.PortErrorParam, path, "Invalid validation for "+ruleUnion+" rule on field: "+path+
+			". Unknown variant '"+key+"'. Allowed variants: "+strings.Join(plan.keys(), ", "))
+		return nil
+	}
+	if value.Type() == t {
+		return vl.validateNested(f, old, name)
+	}
+	var data []byte
+	var err error
+	switch {
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		data = value.Bytes()
+	case value.Kind() == reflect.Map && value.CanInterface():
+		data, err = json.Marshal(value.Interface())
+	default:
+		vl.e.PushDetail(porterr.PortErrorParam, name, "Invalid validation for "+ruleUnion+" rule on field: "+name+
+			". Type "+value.Type().String()+" does not match variant '"+key+"'")
+		return nil
+	}
+	variant := reflect.New(t)
+	if err == nil {
+		err = json.Unmarshal(data, variant.Interface())
+	}
+	if err != nil {
+		vl.e.PushDetail(porterr.PortErrorParam, name, "Invalid validation for "+ruleUnion+" rule on field: "+name+
+			". Value does not match variant '"+key+"': "+err.Error())
+		return nil
+	}
+	return vl.validateStruct(variant.Elem(), reflect.Value{}, name)
+}
+
+// keys sorted discriminator values of variants
+func (plan *unionPlan) keys() []string {
+	var keys = make([]string, 0, len(plan.variants))
+	for key := range plan.variants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strings"
+)
+
+// UpdateValidationCallback function that performs validation rule comparing value with old one
+type UpdateValidationCallback func(old reflect.Value, val reflect.Value, args ...string) bool
+
+// Basic update rules. Applied by ValidateUpdate and ignored by ValidateStruct
+var basicUpdateRules = map[string]UpdateValidationCallback{
+	// Check if value change is allowed transition
+	"transition": IsTransitionValid,
+	// Check if value is not changed
+	"immutable": IsImmutableValid,
+	// Check if value is not changed once it is set
+	"writeOnce": IsWriteOnceValid,
+	// Check if value is not decreased
+	"monotonic": IsMonotonicValid,
+}
+
+// Will be used in update validation
+var actualUpdateRules map[string]UpdateValidationCallback
+
+// Separator of states in transition
+// Example: transition~draft>paid,paid>shipped
+const transitionTo = ">"
+
+// Registered transitions. Name -> old state -> allowed new states
+var transitions = make(map[string]map[string][]string)
+
+// PrepareUpdateValidationRules func to append basicUpdateRules or replace existing rules
+func PrepareUpdateValidationRules(customUpdateRules map[string]UpdateValidationCallback) {
+	actualUpdateRules = make(map[string]UpdateValidationCallback)
+	for s, callback := range basicUpdateRules {
+		actualUpdateRules[s] = callback
+	}
+	for s, callback := range customUpdateRules {
+		actualUpdateRules[s] = callback
+	}
+	resetPlans()
+}
+
+// RegisterTransitions register named allowed transitions between states
+// Transitions could be used in transition rule by name with @ prefix
+// Example
+// v.RegisterTransitions("OrderStatus", map[string][]string{"draft": {"paid"}, "paid": {"shipped", "draft"}})
+// valid:"enum~@OrderStatus;transition~@OrderStatus"
+func RegisterTransitions[T comparable](name string, allowed map[T][]T) porterr.IError {
+	if !validName(name) {
+		return porterr.New(porterr.PortErrorArgument, "Invalid transitions name '"+name+"'")
+	}
+	var states = make(map[string][]string, len(allowed))
+	for from, to := range allowed {
+		key := enumString(reflect.ValueOf(from))
+		for i := range to {
+			states[key] = append(states[key], enumString(reflect.ValueOf(to[i])))
+		}
+	}
+	transitions[name] = states
+	resetPlans()
+	return nil
+}
+
+// UnregisterTransitions remove registered transitions
+func UnregisterTransitions(name string) {
+	delete(transitions, name)
+	resetPlans()
+}
+
+// IsUpdateRuleValid check value and old value with update rule
+// Unknown rules are ignored
+func IsUpdateRuleValid(old reflect.Value, val reflect.Value, rule ValidationRule) bool {
+	callback, ok := actualUpdateRules[rule.Name]
+	if !ok {
+		return true
+	}
+	return callback(old, val, rule.Args...)
+}
+
+// IsTransitionValid check if value is not changed or changed to allowed state
+// Value could be set to any state if old value is empty
+// Example: transition~@OrderStatus, transition~draft>paid,paid>shipped
+func IsTransitionValid(old reflect.Value, val reflect.Value, args ...string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	old, val = derefValue(old), derefValue(val)
+	if !old.IsValid() || old.IsZero() {
+		return true
+	}
+	from, to := enumString(old), enumString(val)
+	if from == to {
+		return true
+	}
+	states, ok := transitionStates(args[0])
+	if !ok {
+		return false
+	}
+	for _, state := range states[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImmutableValid check if value is not changed
+func IsImmutableValid(old reflect.Value, val reflect.Value, args ...string) bool {
+	return isEqualValue(old, val)
+}
+
+// IsWriteOnceValid check if value is not changed once it is set. Empty value could be set
+func IsWriteOnceValid(old reflect.Value, val reflect.Value, args ...string) bool {
+	old = derefValue(old)
+	if !old.IsValid() || old.IsZero() {
+		return true
+	}
+	return isEqualValue(old, val)
+}
+
+// IsMonotonicValid check if number, numeric string, duration or time is not decreased
+// Value could be set if old value is nil. Values of other kinds are not checked
+func IsMonotonicValid(old reflect.Value, val reflect.Value, args ...string) bool {
+	old, val = derefValue(old), derefValue(val)
+	if !old.IsValid() {
+		return true
+	}
+	if !val.IsValid() {
+		return false
+	}
+	if isEqualValue(old, val) {
+		return true
+	}
+	if from, ok := timeValue(old); ok {
+		to, _ := timeValue(val)
+		return !to.Before(from)
+	}
+	from, ok := numberValue(old)
+	if !ok {
+		return !isNumber(old)
+	}
+	to, ok := numberValue(val)
+	return ok && to.Cmp(from) >= 0
+}
+
+// isEqualValue check if values are equal. Times and big numbers are compared by value
+func isEqualValue(a reflect.Value, b reflect.Value) bool {
+	a, b = derefValue(a), derefValue(b)
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if ta, ok := timeValue(a); ok {
+		tb, ok := timeValue(b)
+		return ok && ta.Equal(tb)
+	}
+	if ra, ok := bigNumber(a); ok {
+		rb, ok := bigNumber(b)
+		if !ok || ra == nil || rb == nil {
+			return ok && ra == rb
+		}
+		return ra.Cmp(rb) == 0
+	}
+	if !a.CanInterface() || !b.CanInterface() {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// transitionStates allowed transitions from registered name or list of transitions
+func transitionStates(arg string) (map[string][]string, bool) {
+	if strings.HasPrefix(arg, enumRef) {
+		states, ok := transitions[strings.TrimPrefix(arg, enumRef)]
+		return states, ok
+	}
+	var states = make(map[string][]string)
+	for _, transition := range strings.Split(arg, ",") {
+		from, to, ok := strings.Cut(transition, transitionTo)
+		if !ok || from == "" {
+			return nil, false
+		}
+		states[from] = append(states[from], to)
+	}
+	return states, true
+}
+
+// derefValue get value of pointer. Nil pointer is returned as invalid value
+func derefValue(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+// validateUpdate apply update rules and push error details
+// Built-in rules get underlying values of sql.Null* types and driver.Valuer, custom rules get values as is
+func (vl *validator) validateUpdate(f reflect.Value, old reflect.Value, name string, rules ValidationRules) {
+	value, oldValue := UnwrapValue(f), UnwrapValue(old)
+	for _, rule := range rules {
+		v, o := f, old
+		if isBasicRule(rule.Name) {
+			v, o = value, oldValue
+		}
+		if !IsUpdateRuleValid(o, v, rule) {
+			vl.e.PushDetail(porterr.PortErrorParam, name, updateRuleMessage(rule, name, oldValue, value))
+		}
+	}
+}
+
+// updateRuleMessage prepare error message for invalid update rule
+func updateRuleMessage(rule ValidationRule, fieldName string, old reflect.Value, val reflect.Value) string {
+	var message = "Invalid validation for " + rule.Name + " rule on field: " + fieldName
+	switch rule.Name {
+	case "transition":
+		message += ". Cannot change from " + enumString(derefValue(old)) + " to " + enumString(derefValue(val))
+	case "immutable", "writeOnce":
+		message += ". Value could not be changed"
+	case "monotonic":
+		message += ". Value could not be decreased"
+	}
+	return message
+}
+
+// checkTransitionArgs check if referenced transitions are registered or list of transitions is valid
+func checkTransitionArgs(t reflect.Type, args ...string) porterr.IError {
+	if _, ok := transitionStates(args[0]); !ok {
+		if strings.HasPrefix(args[0], enumRef) {
+			return porterr.New(porterr.PortErrorArgument, "Transitions "+args[0]+" are not registered")
+		}
+		return porterr.New(porterr.PortErrorArgument, "Invalid transitions "+args[0])
+	}
+	return nil
+}
+
+// oldField field of old struct
+func oldField(old reflect.Value, i int) reflect.Value {
+	if !old.IsValid() {
+		return old
+	}
+	return old.Field(i)
+}
+
+// oldElem element of old pointer or interface. Returns invalid value for nil
+func oldElem(old reflect.Value) reflect.Value {
+	if !old.IsValid() || (old.Kind() != reflect.Ptr && old.Kind() != reflect.Interface) || old.IsNil() {
+		return reflect.Value{}
+	}
+	return old.Elem()
+}
+
+// oldIndex element of old collection. Returns invalid value if there is no such element
+func oldIndex(old reflect.Value, i int) reflect.Value {
+	if !old.IsValid() || (old.Kind() != reflect.Slice && old.Kind() != reflect.Array) || i >= old.Len() {
+		return reflect.Value{}
+	}
+	return old.Index(i)
+}
+
+// oldMapIndex value of old map. Returns invalid value if there is no such key
+func oldMapIndex(old reflect.Value, key reflect.Value) reflect.Value {
+	if !old.IsValid() || old.Kind() != reflect.Map || old.IsNil() || !key.Type().AssignableTo(old.Type().Key()) {
+		return reflect.Value{}
+	}
+	return old.MapIndex(key)
+}
@@ -0,0 +1,107 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Default name of value validated by Var
+const varName = "value"
+
+// Variable value validated by Vars
+type Variable struct {
+	// Name of value used in errors
+	Name string
+	// Value to validate
+	Value interface{}
+	// Rules in tag notation
+	Rules string
+}
+
+// varPlanKey key of compiled rules of value
+type varPlanKey struct {
+	// Rules in tag notation
+	rules string
+	// Type of value
+	t reflect.Type
+}
+
+// varPlan compiled rules of value
+type varPlan struct {
+	// Compiled rules
+	rules valuePlan
+	// Compile error
+	e porterr.IError
+}
+
+// MaxVarPlans maximum number of compiled rules of values in cache
+// Rules built at runtime are compiled on each call when cache is full. Zero or negative number disables the limit
+var MaxVarPlans = 1024
+
+// Compiled rules of values. varPlanKey -> *varPlan
+var varPlans sync.Map
+
+// Number of compiled rules in varPlans
+var varPlansCount int64
+
+// Var validate single value with rules in tag notation
+// Name of value in errors is optional, "value" is used by default
+// Example
+// e := v.Var(limit, "range~1:100", "limit")
+func Var(v interface{}, rules string, name ...string) porterr.IError {
+	variable := Variable{Name: varName, Value: v, Rules: rules}
+	if len(name) > 0 {
+		variable.Name = name[0]
+	}
+	return Vars(variable)
+}
+
+// Vars validate several values with rules in tag notation
+// Errors of all values are returned in one validation error
+// Example
+// e := v.Vars(v.Variable{Name: "limit", Value: limit, Rules: "range~1:100"}, v.Variable{Name: "sort", Value: sort, Rules: "enum~asc,desc"})
+func Vars(vars ...Variable) porterr.IError {
+	vl := newValidator()
+	for _, variable := range vars {
+		val := reflect.ValueOf(variable.Value)
+		if !val.IsValid() {
+			val = reflect.ValueOf(&variable.Value).Elem()
+		}
+		plan := getVarPlan(variable.Rules, val.Type())
+		if plan.e != nil {
+			return plan.e
+		}
+		if ce := vl.validateNested(val, reflect.Value{}, variable.Name); ce != nil {
+			return ce
+		}
+		vl.validateValue(val, reflect.Value{}, variable.Name, &plan.rules)
+	}
+	return vl.e.IfDetails()
+}
+
+// getVarPlan get compiled rules for type of value
+// Compiled rules are cached until MaxVarPlans is reached
+func getVarPlan(rules string, t reflect.Type) *varPlan {
+	key := varPlanKey{rules: rules, t: t}
+	if plan, ok := varPlans.Load(key); ok {
+		return plan.(*varPlan)
+	}
+	plan := &varPlan{}
+	compiled, e := compileRules(rules)
+	if e == nil {
+		plan.rules, e = compileValuePlan(compiled, t)
+	}
+	if e != nil {
+		plan.e = porterr.New(porterr.PortErrorParser, "Rules "+rules+". "+e.Error())
+	}
+	if MaxVarPlans > 0 && atomic.LoadInt64(&varPlansCount) >= int64(MaxVarPlans) {
+		return plan
+	}
+	stored, loaded := varPlans.LoadOrStore(key, plan)
+	if !loaded {
+		atomic.AddInt64(&varPlansCount, 1)
+	}
+	return stored.(*varPlan)
+}
@@ -0,0 +1,333 @@
+package v
+
+import (
+	"github.com/dimonrus/porterr"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// MaxDepth maximum depth of nested structs
+// Validation of deeper levels is stopped and reported as error
+var MaxDepth = 32
+
+// ValidateUnexported enable validation of unexported fields
+// Values of unexported fields are read through addressable copy of struct, so rules get them as exported ones
+var ValidateUnexported = false
+
+// validator state of value validation
+type validator struct {
+	// Validation error with details
+	e porterr.IError
+	// Current depth of nested structures
+	depth int
+	// Walked pointers, maps and slices
+	visited map[visit]*visitResult
+	// Indexes of error details repeated for shared references
+	repeated map[int]struct{}
+	// Update rules are applied comparing values with old ones
+	update bool
+}
+
+// visitResult result of walked reference
+type visitResult struct {
+	// Path of the first walk
+	path string
+	// Walk is finished
+	done bool
+	// Range of error details pushed by the walk
+	from, to int
+}
+
+// visit visited reference
+type visit struct {
+	// Type of reference
+	t reflect.Type
+	// Address of reference
+	ptr uintptr
+	// Length of slice
+	len int
+}
+
+// newValidator init validator state
+func newValidator() *validator {
+	return &validator{e: porterr.HttpValidationError()}
+}
+
+// validateStruct validate struct value and push error details
+// Field names are prefixed with path of the struct. Old is the same struct before update or invalid value
+// Returns error if validation plan can not be compiled
+func (vl *validator) validateStruct(ve reflect.Value, old reflect.Value, path string) porterr.IError {
+	plan := getStructPlan(ve.Type())
+	if plan.e != nil {
+		return plan.e
+	}
+	if vl.depth >= MaxDepth {
+		vl.e.PushDetail(porterr.PortErrorRecursion, path, "Maximum depth "+strconv.Itoa(MaxDepth)+" of nested structs exceeded on field: "+path)
+		return nil
+	}
+	if old.IsValid() && old.Type() != ve.Type() {
+		old = reflect.Value{}
+	}
+	if ValidateUnexported {
+		ve, old = addressable(ve), addressable(old)
+	}
+	vl.depth++
+	defer func() { vl.depth-- }()
+	var f, o reflect.Value
+	var name string
+	for _, field := range plan.fields {
+		if field.unexported && !ValidateUnexported {
+			continue
+		}
+		f = ve.Field(field.index)
+		o = oldField(old, field.index)
+		if field.unexported || field.embedded {
+			f, o = exportedValue(f), exportedValue(o)
+		}
+		name = fieldPath(path, field.name)
+		if field.embedded {
+			vl.validateValue(f, o, name, &field.rules)
+			if ce := vl.validateEmbedded(f, o, path); ce != nil {
+				return ce
+			}
+			continue
+		}
+		if field.union != nil {
+			if ce := vl.validateUnion(ve, f, o, path, name, field.union); ce != nil {
+				return ce
+			}
+		} else if ce := vl.validateNested(f, o, name); ce != nil {
+			return ce
+		}
+		vl.validateValue(f, o, name, &field.rules)
+	}
+	vl.validateGroups(ve, path, plan.groups)
+	return nil
+}
+
+// validateEmbedded validate embedded struct with fields promoted to path of parent struct
+func (vl *validator) validateEmbedded(f reflect.Value, old reflect.Value, path string) porterr.IError {
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return nil
+		}
+		key, ok := vl.enter(f, path)
+		if !ok {
+			return nil
+		}
+		defer vl.leave(key)
+		f, old = f.Elem(), oldElem(old)
+	}
+	return vl.validateStruct(f, old, path)
+}
+
+// validateNested validate structs contained in value
+// Pointers and interfaces are dereferenced, slices, arrays and maps are walked
+// Each reference is walked once, errors of shared references are repeated with each path and cycles are not followed
+func (vl *validator) validateNested(f reflect.Value, old reflect.Value, path string) porterr.IError {
+	switch f.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if f.IsNil() {
+			return nil
+		}
+		key, ok := vl.enter(f, path)
+		if !ok {
+			return nil
+		}
+		defer vl.leave(key)
+	}
+	switch f.Kind() {
+	case reflect.Struct:
+		return vl.validateStruct(f, old, path)
+	case reflect.Ptr, reflect.Interface:
+		if f.IsNil() {
+			return nil
+		}
+		return vl.validateNested(f.Elem(), oldElem(old), path)
+	case reflect.Slice, reflect.Array:
+		if !hasNested(f.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < f.Len(); i++ {
+			if ce := vl.validateNested(f.Index(i), oldIndex(old, i), indexName(path, i)); ce != nil {
+				return ce
+			}
+		}
+	case reflect.Map:
+		if !hasNested(f.Type().Elem()) {
+			return nil
+		}
+		for _, key := range sortedKeys(f) {
+			if ce := vl.validateNested(f.MapIndex(key), oldMapIndex(old, key), mapKeyName(path, key)); ce != nil {
+				return ce
+			}
+		}
+	}
+	return nil
+}
+
+// enter mark reference as walked
+// Returns false if reference is already walked. Errors of finished walk are repeated with the path,
+// reference which walk is not finished is a cycle and it is not followed
+func (vl *validator) enter(f reflect.Value, path string) (visit, bool) {
+	key := visit{t: f.Type(), ptr: f.Pointer()}
+	if f.Kind() == reflect.Slice {
+		key.len = f.Len()
+	}
+	if vl.visited == nil {
+		vl.visited = make(map[visit]*visitResult)
+	} else if result, ok := vl.visited[key]; ok {
+		if result.done {
+			vl.repeat(result, path)
+		}
+		return key, false
+	}
+	vl.visited[key] = &visitResult{path: path, from: len(vl.e.GetDetails())}
+	return key, true
+}
+
+// leave mark walk of reference as finished
+func (vl *validator) leave(key visit) {
+	result := vl.visited[key]
+	result.done, result.to = true, len(vl.e.GetDetails())
+}
+
+// repeat push errors of walked reference with another path
+// Repeated errors are not repeated again, so number of errors grows linearly with number of shared references
+func (vl *validator) repeat(result *visitResult, path string) {
+	details := vl.e.GetDetails()
+	count := len(details)
+	if vl.repeated == nil {
+		vl.repeated = make(map[int]struct{})
+	}
+	for i, detail := range details[result.from:result.to] {
+		name := detail.Origin().Name
+		if _, ok := vl.repeated[result.from+i]; ok || !strings.HasPrefix(name, result.path) {
+			continue
+		}
+		suffix := strings.TrimPrefix(name, result.path)
+		switch {
+		case result.path != "" || suffix == "" || suffix[0] == '[':
+			name = path + suffix
+		case path != "":
+			name = path + "." + suffix
+		}
+		message := strings.Replace(detail.Error(), detail.Origin().Name, name, 1)
+		vl.e.PushDetail(detail.GetCode(), name, message)
+		vl.repeated[count] = struct{}{}
+		count++
+	}
+}
+
+// addressable get addressable copy of struct, so values of its unexported fields could be read
+func addressable(ve reflect.Value) reflect.Value {
+	if !ve.IsValid() || ve.CanAddr() || !ve.CanInterface() {
+		return ve
+	}
+	return pointerTo(ve).Elem()
+}
+
+// exportedValue get value of unexported field of addressable struct as exported one
+// Other values are returned as is
+func exportedValue(f reflect.Value) reflect.Value {
+	if !f.IsValid() || f.CanInterface() || !f.CanAddr() {
+		return f
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// elemValue get collection element for rules
+// Element of interface type is returned as pointer to its value, so rules check the value and nil is nil pointer
+func elemValue(f reflect.Value) reflect.Value {
+	if f.Kind() != reflect.Interface {
+		return f
+	}
+	if f.IsNil() || !f.CanInterface() {
+		return reflect.Zero(reflect.PtrTo(f.Type()))
+	}
+	return pointerTo(f.Elem())
+}
+
+// hasNested check if values of type could contain structs
+func hasNested(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasNested(t.Elem())
+	}
+	return false
+}
+
+// validateValue apply rules to value and dive into collection elements
+// Built-in rules get underlying values of sql.Null* types and driver.Valuer, custom rules get values as is
+// Update rules are applied if old value exists
+func (vl *validator) validateValue(f reflect.Value, old reflect.Value, name string, plan *valuePlan) {
+	if vl.update && old.IsValid() {
+		vl.validateUpdate(f, old, name, plan.update)
+	}
+	value, unwrapped := unwrapValue(f)
+	for _, rule := range plan.rules {
+		if (unwrapped && !isUnwrappedRuleValid(f, value, rule)) || (!unwrapped && !IsRuleValid(f, rule)) {
+			vl.e.PushDetail(porterr.PortErrorParam, name, ruleErrorMessage(rule, name, value.Type()))
+		}
+	}
+	if plan.dive == nil && plan.keys == nil {
+		return
+	}
+	f, old = value, UnwrapValue(old)
+	for f.Kind() == reflect.Ptr || f.Kind() == reflect.Interface {
+		if f.IsNil() {
+			return
+		}
+		f, old = f.Elem(), oldElem(old)
+	}
+	switch f.Kind() {
+	case reflect.Slice, reflect.Array:
+		if plan.dive == nil {
+			return
+		}
+		for i := 0; i < f.Len(); i++ {
+			vl.validateValue(elemValue(f.Index(i)), oldIndex(old, i), indexName(name, i), plan.dive)
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(f) {
+			keyName := mapKeyName(name, key)
+			for _, rule := range plan.keys {
+				if !IsRuleValid(key, rule) {
+					vl.e.PushDetail(porterr.PortErrorParam, keyName, ruleErrorMessage(rule, keyName+" key", key.Type()))
+				}
+			}
+			if plan.dive != nil {
+				vl.validateValue(elemValue(f.MapIndex(key)), oldMapIndex(old, key), keyName, plan.dive)
+			}
+		}
+	}
+}
+
+// isUnwrappedRuleValid check value of sql.Null* type or driver.Valuer with rule
+// Built-in rules get underlying value, custom rules get original value
+func isUnwrappedRuleValid(val reflect.Value, unwrapped reflect.Value, rule ValidationRule) bool {
+	if len(rule.Alternatives) == 0 {
+		if isBasicRule(rule.Name) {
+			return IsRuleValid(unwrapped, rule)
+		}
+		return IsRuleValid(val, rule)
+	}
+	var valid bool
+	for _, alternative := range rule.Alternatives {
+		valid = true
+		for _, r := range alternative {
+			if !isUnwrappedRuleValid(val, unwrapped, r) {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			break
+		}
+	}
+	return valid != rule.Not
+}